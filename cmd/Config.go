@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.io/xhkzeroone/goframex/internal/config"
+	ymlx "github.io/xhkzeroone/goframex/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+const configUsage = `Usage:
+  Main config dump   [-format yaml|json]           in cấu hình đã merge (che secret)
+  Main config schema [-strict] [-o config.schema.json]  xuất JSON Schema cho config-*.yml`
+
+// runConfigCommand xử lý subcommand "config"
+func runConfigCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing config subcommand\n%s", configUsage)
+	}
+
+	switch args[0] {
+	case "dump":
+		fs := flag.NewFlagSet("config dump", flag.ContinueOnError)
+		format := fs.String("format", "yaml", "output format: yaml or json")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		return dumpConfig(os.Stdout, *format)
+	case "schema":
+		fs := flag.NewFlagSet("config schema", flag.ContinueOnError)
+		strict := fs.Bool("strict", false, "reject keys that are not declared in the config structs")
+		output := fs.String("o", "", "output file (default stdout)")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+		return exportSchema(*output, *strict)
+	default:
+		return fmt.Errorf("unknown config subcommand %q\n%s", args[0], configUsage)
+	}
+}
+
+func dumpConfig(w io.Writer, format string) error {
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return err
+	}
+	dump, err := ymlx.Dump(cfg)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(dump)
+	case "yaml":
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		defer enc.Close()
+		return enc.Encode(dump)
+	default:
+		return fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+func exportSchema(output string, strict bool) error {
+	opts := []ymlx.SchemaOption{ymlx.SchemaTitle("goframex configuration")}
+	if strict {
+		opts = append(opts, ymlx.SchemaStrict())
+	}
+	data, err := json.MarshalIndent(ymlx.JSONSchema(&config.Config{}, opts...), "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if output == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(output, data, 0o644)
+}
@@ -10,6 +10,19 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		if err := runConfigCommand(os.Args[2:]); err != nil {
+			log.Fatalf("config command failed: %v", err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrateCommand(os.Args[2:]); err != nil {
+			log.Fatalf("migrate command failed: %v", err)
+		}
+		return
+	}
+
 	app, err := bootstrap.NewApp()
 	if err != nil {
 		log.Fatalf("Failed to initialize usecase: %v", err)
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.io/xhkzeroone/goframex/internal/config"
+	"github.io/xhkzeroone/goframex/internal/migrations"
+	"github.io/xhkzeroone/goframex/pkg/database/gormx"
+	"github.io/xhkzeroone/goframex/pkg/database/gormx/migrate"
+)
+
+const migrateUsage = `Usage:
+  Main migrate up      [-dry-run]   chạy toàn bộ migration chưa áp dụng
+  Main migrate down    [-dry-run] [-n 1]   rollback n migration gần nhất
+  Main migrate to      [-dry-run] <version>   đưa database về đúng version
+  Main migrate status                  liệt kê trạng thái migration`
+
+// runMigrateCommand xử lý subcommand "migrate"
+func runMigrateCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing migrate subcommand\n%s", migrateUsage)
+	}
+
+	fs := flag.NewFlagSet("migrate "+args[0], flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "print SQL without applying it")
+	steps := fs.Int("n", 1, "number of migrations to roll back (down)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return err
+	}
+	ds, err := gormx.Open(cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer ds.Close()
+
+	var opts []migrate.Option
+	if *dryRun {
+		opts = append(opts, migrate.WithDryRun(os.Stdout))
+	}
+	m := migrate.New(ds, opts...)
+	if err := m.AddFS(migrations.FS, "."); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		return m.Up(ctx)
+	case "down":
+		return m.Down(ctx, *steps)
+	case "to":
+		if fs.NArg() != 1 {
+			return fmt.Errorf("missing target version\n%s", migrateUsage)
+		}
+		version, err := strconv.ParseInt(fs.Arg(0), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid version %q: %w", fs.Arg(0), err)
+		}
+		return m.To(ctx, version)
+	case "status":
+		status, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		return printMigrationStatus(os.Stdout, status)
+	default:
+		return fmt.Errorf("unknown migrate subcommand %q\n%s", args[0], migrateUsage)
+	}
+}
+
+func printMigrationStatus(w io.Writer, status []migrate.MigrationStatus) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, s := range status {
+		state, appliedAt := "pending", ""
+		if s.Applied {
+			state = "applied"
+			appliedAt = s.AppliedAt.Local().Format("2006-01-02 15:04:05")
+		}
+		if s.Missing {
+			state = "missing"
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+	}
+	return tw.Flush()
+}
@@ -2,25 +2,32 @@ package bootstrap
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.io/xhkzeroone/goframex/internal/config"
 	"github.io/xhkzeroone/goframex/internal/delivery/http"
 	"github.io/xhkzeroone/goframex/internal/domain"
 	"github.io/xhkzeroone/goframex/internal/infrastructure/database"
 	"github.io/xhkzeroone/goframex/internal/infrastructure/external"
+	"github.io/xhkzeroone/goframex/internal/migrations"
 	uc "github.io/xhkzeroone/goframex/internal/usecase"
 	"github.io/xhkzeroone/goframex/pkg/cache/redisx"
+	ymlx "github.io/xhkzeroone/goframex/pkg/config"
 	"github.io/xhkzeroone/goframex/pkg/database/gormx"
+	"github.io/xhkzeroone/goframex/pkg/database/gormx/migrate"
+	"github.io/xhkzeroone/goframex/pkg/database/gormx/outbox"
 	"github.io/xhkzeroone/goframex/pkg/http/ginx"
 	"github.io/xhkzeroone/goframex/pkg/http/restyx"
 	"github.io/xhkzeroone/goframex/pkg/logger/logrusx"
-	"gorm.io/driver/postgres"
+	"time"
 )
 
 type Infrastructure struct {
 	DB         *gormx.DataSource
 	Cache      *redisx.Redis
 	UserClient *restyx.Client
+	Outbox     *outbox.Outbox
+	Relay      *outbox.Relay
 }
 
 type Repositories struct {
@@ -41,6 +48,7 @@ type Handlers struct {
 
 type Application struct {
 	Config           *config.Config
+	ConfigSource     *ymlx.Config
 	Infrastructure   *Infrastructure
 	Repositories     *Repositories
 	ExternalServices *ExternalServices
@@ -50,11 +58,19 @@ type Application struct {
 }
 
 func (app *Application) Start() error {
+	if app.Infrastructure.Relay != nil {
+		app.Infrastructure.Relay.Start()
+	}
 	return app.Server.Start()
 }
 
 func (app *Application) Stop() error {
-	return app.Server.Stop(context.Background())
+	ctx := context.Background()
+	err := app.Server.Stop(ctx)
+	if app.Infrastructure.Relay != nil {
+		err = errors.Join(err, app.Infrastructure.Relay.Stop(ctx))
+	}
+	return err
 }
 
 func NewApp() (*Application, error) {
@@ -67,7 +83,11 @@ func NewApp() (*Application, error) {
 		FunctionNameFormatter: logrusx.GetFunctionNameFormatter(),
 	})
 
-	cfg, err := config.NewConfig()
+	configSource, err := ymlx.New()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := config.LoadConfig(configSource)
 	if err != nil {
 		return nil, err
 	}
@@ -85,7 +105,7 @@ func NewApp() (*Application, error) {
 	externalServices := initExternalServices(infrastructure)
 
 	// Initialize usecases
-	usecases := initUsecases(repositories, externalServices)
+	usecases := initUsecases(infrastructure, repositories, externalServices)
 
 	// Initialize handlers
 	handlers := initHandlers(usecases)
@@ -95,6 +115,7 @@ func NewApp() (*Application, error) {
 
 	return &Application{
 		Config:           cfg,
+		ConfigSource:     configSource,
 		Infrastructure:   infrastructure,
 		Repositories:     repositories,
 		ExternalServices: externalServices,
@@ -110,6 +131,12 @@ func initInfrastructure(config *config.Config) (*Infrastructure, error) {
 	if err != nil {
 		return nil, err
 	}
+	if config.MigrateOnStart {
+		if err := runMigrations(db); err != nil {
+			_ = db.Close()
+			return nil, err
+		}
+	}
 
 	// Initialize cache
 	cache, err := initCache(config.Cache)
@@ -120,10 +147,19 @@ func initInfrastructure(config *config.Config) (*Infrastructure, error) {
 	// Initialize external service userClient
 	userClient := restyx.New(config.External.UserClient)
 
+	// Initialize outbox relay
+	events := outbox.New(db)
+	relay, err := initRelay(config.Outbox, events, cache)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Infrastructure{
 		DB:         db,
 		Cache:      cache,
 		UserClient: userClient,
+		Outbox:     events,
+		Relay:      relay,
 	}, nil
 }
 
@@ -139,9 +175,11 @@ func initExternalServices(infrastructure *Infrastructure) *ExternalServices {
 	}
 }
 
-func initUsecases(repositories *Repositories, externalServices *ExternalServices) *Usecases {
+func initUsecases(infrastructure *Infrastructure, repositories *Repositories, externalServices *ExternalServices) *Usecases {
+	transactor := database.NewTransactor(infrastructure.DB)
+	events := database.NewEventPublisher(infrastructure.Outbox)
 	return &Usecases{
-		UserUsecase: uc.NewUserUsecase(repositories.UserRepository, externalServices.UserService),
+		UserUsecase: uc.NewUserUsecase(repositories.UserRepository, externalServices.UserService, transactor, events),
 	}
 }
 
@@ -172,12 +210,8 @@ func initServer(config *config.Config, handlers *Handlers, infrastructure *Infra
 }
 
 func initDatabase(cfg *gormx.Config) (*gormx.DataSource, error) {
-	// Build PostgreSQL DSN
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, cfg.SSLMode)
-
-	// Open database with PostgreSQL driver
-	db, err := gormx.Open(cfg, gormx.WithDialector(postgres.Open(dsn)))
+	// Dialector được tạo từ cfg.Driver (mặc định postgres)
+	db, err := gormx.Open(cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -186,6 +220,20 @@ func initDatabase(cfg *gormx.Config) (*gormx.DataSource, error) {
 	return db, nil
 }
 
+// runMigrations áp dụng migration còn thiếu (bảng user, outbox...), advisory lock tránh chạy trùng giữa các replica
+func runMigrations(db *gormx.DataSource) error {
+	m := migrate.New(db)
+	if err := m.AddFS(migrations.FS, "."); err != nil {
+		return err
+	}
+	if err := m.Up(context.Background()); err != nil {
+		return err
+	}
+
+	logrusx.Log.Info("Database migrations applied")
+	return nil
+}
+
 func initCache(cfg *redisx.Config) (*redisx.Redis, error) {
 	cache, err := redisx.New(cfg)
 	if err != nil {
@@ -195,3 +243,46 @@ func initCache(cfg *redisx.Config) (*redisx.Redis, error) {
 	logrusx.Log.Info("Cache initialized successfully")
 	return cache, nil
 }
+
+// initRelay tạo relay publish event từ outbox theo cấu hình, trả về nil nếu relay bị tắt
+func initRelay(cfg *config.Outbox, events *outbox.Outbox, cache *redisx.Redis) (*outbox.Relay, error) {
+	if cfg == nil || !cfg.Enabled {
+		return nil, nil
+	}
+
+	var sink outbox.Sink
+	switch cfg.Sink {
+	case "", "log":
+		sink = outbox.LogSink()
+	case "redis":
+		var stream func(msg *outbox.Message) string
+		if cfg.Stream != "" {
+			stream = func(*outbox.Message) string { return cfg.Stream }
+		}
+		sink = outbox.RedisStreamSink(cache, stream)
+	case "webhook":
+		if cfg.Webhook == nil {
+			return nil, fmt.Errorf("outbox: webhook sink requires outbox.webhook config")
+		}
+		sink = outbox.WebhookSink(restyx.New(cfg.Webhook), cfg.WebhookPath)
+	default:
+		return nil, fmt.Errorf("outbox: unknown sink %q", cfg.Sink)
+	}
+
+	var opts []outbox.RelayOption
+	if cfg.PollInterval > 0 {
+		opts = append(opts, outbox.WithPollInterval(cfg.PollInterval))
+	}
+	if cfg.BatchSize > 0 {
+		opts = append(opts, outbox.WithBatchSize(cfg.BatchSize))
+	}
+	if cfg.MaxAttempts > 0 {
+		opts = append(opts, outbox.WithMaxAttempts(cfg.MaxAttempts))
+	}
+	if cfg.Retention > 0 {
+		opts = append(opts, outbox.WithCleanup(cfg.Retention, time.Hour))
+	}
+
+	logrusx.Log.Info("Outbox relay initialized")
+	return outbox.NewRelay(events, sink, opts...), nil
+}
@@ -1,10 +1,14 @@
 package bootstrap
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.io/xhkzeroone/goframex/pkg/database/gormx"
 	"github.io/xhkzeroone/goframex/pkg/http/ginx"
 	"github.io/xhkzeroone/goframex/pkg/logger/logrusx"
 )
@@ -29,6 +33,16 @@ func LoggingMiddleware() ginx.Middleware {
 	}
 }
 
+// ReadYourWritesMiddleware - Gắn session gormx vào request context để các lần đọc ngay sau khi ghi đi vào primary
+func ReadYourWritesMiddleware() ginx.Middleware {
+	return func(next ginx.HandlerFunc) ginx.HandlerFunc {
+		return func(ctx *ginx.Context) error {
+			ctx.Request = ctx.Request.WithContext(gormx.WithReadYourWrites(ctx.Request.Context()))
+			return next(ctx)
+		}
+	}
+}
+
 // AuthMiddleware - Middleware cần truy cập database để validate token
 func AuthMiddleware(container *MiddlewareContainer) ginx.Middleware {
 	return func(next ginx.HandlerFunc) ginx.HandlerFunc {
@@ -143,28 +157,36 @@ func AdminAuthMiddleware(container *MiddlewareContainer) ginx.Middleware {
 	}
 }
 
+// errRollbackResponse dùng để rollback khi handler trả về response lỗi (4xx/5xx) mà không trả error
+var errRollbackResponse = errors.New("rollback: error response")
+
 // DatabaseTransactionMiddleware - Middleware để wrap request trong database transaction
 func DatabaseTransactionMiddleware(container *MiddlewareContainer) ginx.Middleware {
 	return func(next ginx.HandlerFunc) ginx.HandlerFunc {
 		return func(ctx *ginx.Context) error {
-			// Bắt đầu transaction
-			// tx := container.Infrastructure.DB.Begin()
-			// if tx.Error != nil {
-			//     return tx.Error
-			// }
-
-			// Set transaction vào context
-			// ctx.Set("db_tx", tx)
-
-			err := next(ctx)
-
-			// Commit hoặc rollback transaction
-			// if err != nil {
-			//     tx.Rollback()
-			// } else {
-			//     tx.Commit()
-			// }
+			var handlerErr error
+			reqCtx := ctx.Request.Context()
+			err := gormx.Transactional(reqCtx, container.Infrastructure.DB, func(txCtx context.Context) error {
+				// Set transaction vào context, repository dùng ctx.Request.Context() sẽ tự tham gia transaction
+				ctx.Request = ctx.Request.WithContext(txCtx)
+				handlerErr = next(ctx)
+				if handlerErr != nil {
+					return handlerErr
+				}
+				if ctx.Response() != nil && ctx.Status() >= http.StatusBadRequest {
+					return errRollbackResponse
+				}
+				return nil
+			})
+			// Transaction đã kết thúc, trả lại context gốc cho các middleware phía ngoài
+			ctx.Request = ctx.Request.WithContext(reqCtx)
 
+			if errors.Is(err, errRollbackResponse) {
+				return nil
+			}
+			if handlerErr != nil {
+				return handlerErr
+			}
 			return err
 		}
 	}
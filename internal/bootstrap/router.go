@@ -13,16 +13,19 @@ type MiddlewareContainer struct {
 func RegisterRoutes(server *ginx.Server, handlers *Handlers, middlewareContainer *MiddlewareContainer) {
 	// Global middleware
 	server.Use(LoggingMiddleware())
+	server.Use(ReadYourWritesMiddleware())
 	server.Use(AuthMiddleware(middlewareContainer))
 	server.Use(RateLimitMiddleware(middlewareContainer))
 
 	// User routes với middleware riêng
 	userGroup := server.Group("/users", PermissionMiddleware(middlewareContainer, "user:access"))
-	userGroup.POST("", handlers.UserHandler.CreateUser, ValidationMiddleware())
+	// Các route ghi chạy trong một transaction, handler lỗi hoặc trả về 4xx/5xx sẽ rollback
+	transaction := DatabaseTransactionMiddleware(middlewareContainer)
+	userGroup.POST("", handlers.UserHandler.CreateUser, ValidationMiddleware(), transaction)
 	userGroup.GET("/:id", handlers.UserHandler.GetUserByID)
 	userGroup.GET("", handlers.UserHandler.GetUsers)
-	userGroup.PUT("/:id", handlers.UserHandler.UpdateUser, ValidationMiddleware())
-	userGroup.DELETE("/:id", handlers.UserHandler.DeleteUser)
+	userGroup.PUT("/:id", handlers.UserHandler.UpdateUser, ValidationMiddleware(), transaction)
+	userGroup.DELETE("/:id", handlers.UserHandler.DeleteUser, transaction)
 
 	// API v1 routes với versioning
 	apiV1Group := server.Group("/api/v1", PermissionMiddleware(middlewareContainer, "api:access"))
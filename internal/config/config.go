@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.io/xhkzeroone/goframex/pkg/cache/redisx"
 	ymlx "github.io/xhkzeroone/goframex/pkg/config"
 	"github.io/xhkzeroone/goframex/pkg/database/gormx"
@@ -15,15 +17,43 @@ type Config struct {
 	Cache    *redisx.Config  `mapstructure:"cache" yaml:"cache"`
 	Logger   *logrusx.Config `mapstructure:"logger" yaml:"logger"`
 	External *External       `mapstructure:"external" yaml:"external"`
+	Outbox   *Outbox         `mapstructure:"outbox" yaml:"outbox"`
+	// MigrateOnStart chạy migration của ứng dụng trước khi khởi tạo repository và outbox relay
+	MigrateOnStart bool `mapstructure:"migrate_on_start" yaml:"migrate_on_start"`
 }
 
 type External struct {
 	UserClient *restyx.Config `mapstructure:"user-client" yaml:"user-client"`
 }
 
+// Outbox cấu hình relay publish event từ bảng outbox
+type Outbox struct {
+	Enabled bool `mapstructure:"enabled" yaml:"enabled"`
+	// Sink: log (mặc định), redis, webhook
+	Sink string `mapstructure:"sink" yaml:"sink"`
+	// Stream là Redis Stream nhận event khi sink=redis, bỏ trống dùng "events.<aggregateType>"
+	Stream      string         `mapstructure:"stream" yaml:"stream"`
+	Webhook     *restyx.Config `mapstructure:"webhook" yaml:"webhook"`
+	WebhookPath string         `mapstructure:"webhook_path" yaml:"webhook_path"`
+
+	PollInterval time.Duration `mapstructure:"poll_interval" yaml:"poll_interval"`
+	BatchSize    int           `mapstructure:"batch_size" yaml:"batch_size"`
+	MaxAttempts  int           `mapstructure:"max_attempts" yaml:"max_attempts"`
+	Retention    time.Duration `mapstructure:"retention" yaml:"retention"`
+}
+
 func NewConfig() (*Config, error) {
+	source, err := ymlx.New()
+	if err != nil {
+		return nil, err
+	}
+	return LoadConfig(source)
+}
+
+// LoadConfig đổ cấu hình từ source vào Config của ứng dụng
+func LoadConfig(source *ymlx.Config) (*Config, error) {
 	config := &Config{}
-	if err := ymlx.Load(config); err != nil {
+	if err := source.Unmarshal(config); err != nil {
 		return nil, err
 	}
 	return config, nil
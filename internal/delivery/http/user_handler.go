@@ -3,7 +3,6 @@ package http
 import (
 	"github.io/xhkzeroone/goframex/internal/domain"
 	"net/http"
-	"strconv"
 
 	"github.io/xhkzeroone/goframex/pkg/http/ginx"
 	"github.io/xhkzeroone/goframex/pkg/logger/logrusx"
@@ -22,13 +21,8 @@ func NewUserHandler(userUsecase domain.UserUsecase) *UserHandler {
 func (h *UserHandler) CreateUser(ctx *ginx.Context) error {
 	var req CreateUserRequest
 	if err := ctx.Bind(&req); err != nil {
-		logrusx.Log.Errorf("Failed to bind create user request: %v", err)
-		ctx.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "Invalid request body",
-			Message: err.Error(),
-			Code:    http.StatusBadRequest,
-		})
-		return nil
+		logrusx.Log.Warnf("Invalid create user request: %v", err)
+		return err
 	}
 
 	user := req.ToDomain()
@@ -79,22 +73,13 @@ func (h *UserHandler) GetUserByID(ctx *ginx.Context) error {
 }
 
 func (h *UserHandler) GetUsers(ctx *ginx.Context) error {
-	limit := 10
-	offset := 0
-
-	if limitStr := ctx.Query()["limit"]; limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
-			limit = l
-		}
+	var req GetUsersRequest
+	if err := ctx.BindQuery(&req); err != nil {
+		logrusx.Log.Warnf("Invalid get users request: %v", err)
+		return err
 	}
 
-	if offsetStr := ctx.Query()["offset"]; offsetStr != "" {
-		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
-			offset = o
-		}
-	}
-
-	users, err := h.userUsecase.GetAllUsers(ctx.Request.Context(), limit, offset)
+	users, err := h.userUsecase.GetAllUsers(ctx.Request.Context(), req.Limit, req.Offset)
 	if err != nil {
 		logrusx.Log.Errorf("Failed to get users: %v", err)
 		ctx.JSON(http.StatusInternalServerError, ErrorResponse{
@@ -114,7 +99,7 @@ func (h *UserHandler) GetUsers(ctx *ginx.Context) error {
 
 	ctx.JSON(http.StatusOK, SuccessResponse{
 		Message: "Users retrieved successfully",
-		Data:    NewUsersResponse(users, total, limit, offset),
+		Data:    NewUsersResponse(users, total, req.Limit, req.Offset),
 	})
 	return nil
 }
@@ -132,13 +117,8 @@ func (h *UserHandler) UpdateUser(ctx *ginx.Context) error {
 
 	var req UpdateUserRequest
 	if err := ctx.Bind(&req); err != nil {
-		logrusx.Log.Errorf("Failed to bind update user request: %v", err)
-		ctx.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "Invalid request body",
-			Message: err.Error(),
-			Code:    http.StatusBadRequest,
-		})
-		return nil
+		logrusx.Log.Warnf("Invalid update user request: %v", err)
+		return err
 	}
 
 	user := req.ToDomain(id)
@@ -49,8 +49,8 @@ func (r *UpdateUserRequest) ToDomain(id string) *domain.User {
 }
 
 type GetUsersRequest struct {
-	Limit  int `form:"limit" binding:"min=1,max=100"`
-	Offset int `form:"offset" binding:"min=0"`
+	Limit  int `form:"limit,default=10" binding:"min=1,max=100"`
+	Offset int `form:"offset,default=0" binding:"min=0"`
 }
 
 type UserResponse struct {
@@ -0,0 +1,19 @@
+package domain
+
+import "context"
+
+const (
+	AggregateUser = "user"
+
+	EventUserCreated = "user.created"
+)
+
+// Transactor chạy fn trong transaction, các repository dùng ctx truyền vào fn sẽ tham gia transaction
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// EventPublisher ghi event cùng transaction trong ctx, event chỉ được gửi đi sau khi transaction commit
+type EventPublisher interface {
+	Publish(ctx context.Context, aggregateType, aggregateID, eventType string, payload any) error
+}
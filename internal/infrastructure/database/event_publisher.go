@@ -0,0 +1,26 @@
+package database
+
+import (
+	"context"
+
+	"github.io/xhkzeroone/goframex/internal/domain"
+	"github.io/xhkzeroone/goframex/pkg/database/gormx/outbox"
+)
+
+type eventPublisher struct {
+	outbox *outbox.Outbox
+}
+
+// NewEventPublisher ghi event vào outbox, relay sẽ publish sau khi transaction commit
+func NewEventPublisher(o *outbox.Outbox) domain.EventPublisher {
+	return &eventPublisher{outbox: o}
+}
+
+func (p *eventPublisher) Publish(ctx context.Context, aggregateType, aggregateID, eventType string, payload any) error {
+	return p.outbox.Publish(ctx, outbox.Event{
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		Type:          eventType,
+		Payload:       payload,
+	})
+}
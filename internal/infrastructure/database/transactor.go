@@ -0,0 +1,20 @@
+package database
+
+import (
+	"context"
+
+	"github.io/xhkzeroone/goframex/internal/domain"
+	"github.io/xhkzeroone/goframex/pkg/database/gormx"
+)
+
+type transactor struct {
+	db *gormx.DataSource
+}
+
+func NewTransactor(db *gormx.DataSource) domain.Transactor {
+	return &transactor{db: db}
+}
+
+func (t *transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return gormx.Transactional(ctx, t.db, fn)
+}
@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	"github.io/xhkzeroone/goframex/internal/domain"
@@ -22,8 +23,13 @@ type UserModel struct {
 	LastName  string    `gorm:"column:last_name"`
 	Email     string    `gorm:"column:email"`
 	Status    string    `gorm:"column:status"`
-	CreatedAt time.Time `gorm:"column:created_at"`
-	UpdatedAt time.Time `gorm:"column:updated_at"`
+	Password  string    `gorm:"column:password"`
+	Age       int       `gorm:"column:age"`
+	Phone     string    `gorm:"column:phone"`
+	Address   string    `gorm:"column:address"`
+	gormx.Auditable
+	gormx.SoftDeletable
+	gormx.Versioned
 }
 
 func (u *UserModel) TableName() string {
@@ -31,13 +37,9 @@ func (u *UserModel) TableName() string {
 }
 
 func (u *UserModel) BeforeCreate(ctx *gorm.DB) (err error) {
-	u.ID = uuid.New()
-	u.CreatedAt = time.Now()
-	return
-}
-
-func (u *UserModel) BeforeUpdate(ctx *gorm.DB) (err error) {
-	u.UpdatedAt = time.Now()
+	if u.ID == uuid.Nil {
+		u.ID = uuid.New()
+	}
 	return
 }
 
@@ -45,65 +47,97 @@ func (u *UserModel) GetTotal() int {
 	return u.Total
 }
 
+const (
+	userStatusActive   = "active"
+	userStatusInactive = "inactive"
+)
+
+// newUserModel chuyển domain.User sang model, ID rỗng sẽ được sinh trong BeforeCreate
+func newUserModel(user *domain.User) (*UserModel, error) {
+	m := &UserModel{}
+	if user.ID != "" {
+		id, err := uuid.Parse(user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid user id %q: %w", user.ID, err)
+		}
+		m.ID = id
+	}
+	m.apply(user)
+	return m, nil
+}
+
+// apply ghi các field của domain.User vào model, giữ nguyên các cột domain không quản lý
+func (u *UserModel) apply(user *domain.User) {
+	u.UserName = user.Name
+	u.Email = user.Email
+	u.Password = user.Password
+	u.Age = user.Age
+	u.Phone = user.Phone
+	u.Address = user.Address
+	u.Status = userStatusInactive
+	if user.IsActive {
+		u.Status = userStatusActive
+	}
+}
+
+func (u *UserModel) toDomain() *domain.User {
+	return &domain.User{
+		ID:       u.ID.String(),
+		Name:     u.UserName,
+		Email:    u.Email,
+		Password: u.Password,
+		Age:      u.Age,
+		Phone:    u.Phone,
+		Address:  u.Address,
+		IsActive: u.Status == userStatusActive,
+	}
+}
+
+// userRepository đọc user theo ID qua cache Redis của gormx.CachedRepository,
+// cache được xóa sau khi transaction commit khi Update/Delete
 type userRepository struct {
-	db    *gormx.Repository[UserModel, uuid.UUID]
-	cache *redisx.Redis
+	db *gormx.CachedRepository[UserModel, uuid.UUID]
 }
 
 func NewUserRepository(db *gormx.DataSource, cache *redisx.Redis) domain.UserRepository {
 	return &userRepository{
-		db:    gormx.NewRepository[UserModel, uuid.UUID](db),
-		cache: cache,
+		db: gormx.NewCachedRepository(
+			gormx.NewRepository[UserModel, uuid.UUID](db),
+			gormx.NewRedisCache(cache),
+			gormx.WithCacheTTL(30*time.Minute),
+		),
 	}
 }
 
 func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
-	if err := r.db.Create(user).Error; err != nil {
-		logrusx.Log.Errorf("Failed to create user: %v", err)
+	model, err := newUserModel(user)
+	if err != nil {
 		return err
 	}
-
-	// Cache the created user
-	cacheKey := fmt.Sprintf("user:%s", user.ID)
-	if err := r.cache.SetJSON(ctx, cacheKey, user, 30*time.Minute); err != nil {
-		logrusx.Log.Warnf("Failed to cache user: %v", err)
+	if err := r.db.Insert(ctx, model); err != nil {
+		logrusx.Log.Errorf("Failed to create user: %v", err)
+		return err
 	}
+	user.ID = model.ID.String()
 
 	logrusx.Log.Infof("User created successfully: %s", user.ID)
 	return nil
 }
 
 func (r *userRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
-	// Try to get from cache first
-	cacheKey := fmt.Sprintf("user:%s", id)
-	var user domain.User
-	if err := r.cache.GetJSON(ctx, cacheKey, &user); err == nil {
-		logrusx.Log.Infof("User retrieved from cache: %s", id)
-		return &user, nil
-	}
-
-	// If not in cache, get from database
-	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&user).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("user not found: %s", id)
-		}
-		logrusx.Log.Errorf("Failed to get user by ID: %v", err)
+	model, err := r.findModel(ctx, id)
+	if err != nil {
 		return nil, err
 	}
 
-	// Cache the user
-	if err := r.cache.SetJSON(ctx, cacheKey, &user, 30*time.Minute); err != nil {
-		logrusx.Log.Warnf("Failed to cache user: %v", err)
-	}
-
-	logrusx.Log.Infof("User retrieved from database: %s", id)
-	return &user, nil
+	logrusx.Log.Infof("User retrieved: %s", id)
+	return model.toDomain(), nil
 }
 
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
-	var user domain.User
-	if err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
+	var model UserModel
+	if err := r.db.Reader(ctx).Where("email = ?", email).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("user not found with email: %s", email)
 		}
 		logrusx.Log.Errorf("Failed to get user by email: %v", err)
@@ -111,58 +145,78 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*domain.
 	}
 
 	logrusx.Log.Infof("User retrieved by email: %s", email)
-	return &user, nil
+	return model.toDomain(), nil
 }
 
 func (r *userRepository) GetAll(ctx context.Context, limit, offset int) ([]*domain.User, error) {
-	var users []*domain.User
-	if err := r.db.WithContext(ctx).Limit(limit).Offset(offset).Find(&users).Error; err != nil {
+	var models []UserModel
+	if err := r.db.Reader(ctx).Order("created_at").Limit(limit).Offset(offset).Find(&models).Error; err != nil {
 		logrusx.Log.Errorf("Failed to get all users: %v", err)
 		return nil, err
 	}
+	users := make([]*domain.User, len(models))
+	for i := range models {
+		users[i] = models[i].toDomain()
+	}
 
 	logrusx.Log.Infof("Retrieved %d users", len(users))
 	return users, nil
 }
 
+// Update nạp bản ghi hiện có từ primary (không qua cache) rồi ghi qua Repository.Update để áp dụng audit và optimistic lock,
+// trả về gormx.ErrOptimisticLock nếu bản ghi bị sửa đồng thời
 func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
-	if err := r.db.WithContext(ctx).Save(user).Error; err != nil {
-		logrusx.Log.Errorf("Failed to update user: %v", err)
+	model, err := r.findModel(gormx.UsePrimary(ctx), user.ID)
+	if err != nil {
 		return err
 	}
-
-	// Update cache
-	cacheKey := fmt.Sprintf("user:%s", user.ID)
-	if err := r.cache.SetJSON(ctx, cacheKey, user, 30*time.Minute); err != nil {
-		logrusx.Log.Warnf("Failed to update cache for user: %v", err)
+	model.apply(user)
+	if err := r.db.Update(ctx, model); err != nil {
+		logrusx.Log.Errorf("Failed to update user: %v", err)
+		return err
 	}
 
 	logrusx.Log.Infof("User updated successfully: %s", user.ID)
 	return nil
 }
 
+// Delete xóa mềm user (deleted_at)
 func (r *userRepository) Delete(ctx context.Context, id string) error {
-	if err := r.db.WithContext(ctx).Where("id = ?", id).Delete(&domain.User{}).Error; err != nil {
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("user not found: %s", id)
+	}
+	if err := r.db.DeleteByID(ctx, uid); err != nil {
 		logrusx.Log.Errorf("Failed to delete user: %v", err)
 		return err
 	}
 
-	// Remove from cache
-	cacheKey := fmt.Sprintf("user:%s", id)
-	if err := r.cache.Del(ctx, cacheKey).Err(); err != nil {
-		logrusx.Log.Warnf("Failed to remove user from cache: %v", err)
-	}
-
 	logrusx.Log.Infof("User deleted successfully: %s", id)
 	return nil
 }
 
 func (r *userRepository) Count(ctx context.Context) (int64, error) {
 	var count int64
-	if err := r.db.WithContext(ctx).Model(&domain.User{}).Count(&count).Error; err != nil {
+	if err := r.db.Reader(ctx).Model(&UserModel{}).Count(&count).Error; err != nil {
 		logrusx.Log.Errorf("Failed to count users: %v", err)
 		return 0, err
 	}
 
 	return count, nil
 }
+
+func (r *userRepository) findModel(ctx context.Context, id string) (*UserModel, error) {
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %s", id)
+	}
+	model, err := r.db.FindByID(ctx, uid)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("user not found: %s", id)
+		}
+		logrusx.Log.Errorf("Failed to get user by ID: %v", err)
+		return nil, err
+	}
+	return model, nil
+}
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS user_tbl (
+    id          UUID PRIMARY KEY,
+    partner_id  VARCHAR(255),
+    total       INTEGER NOT NULL DEFAULT 0,
+    user_name   VARCHAR(255),
+    first_name  VARCHAR(255),
+    last_name   VARCHAR(255),
+    email       VARCHAR(255),
+    status      VARCHAR(50),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_by  VARCHAR(64),
+    updated_by  VARCHAR(64),
+    deleted_at  TIMESTAMPTZ,
+    version     BIGINT NOT NULL DEFAULT 1
+);
+
+-- user_tbl có sẵn từ trước khi dùng migration (AutoMigrate) bỏ qua CREATE TABLE ở trên,
+-- bổ sung các cột audit, soft delete và version mà UserModel cần
+ALTER TABLE user_tbl ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+ALTER TABLE user_tbl ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+ALTER TABLE user_tbl ADD COLUMN IF NOT EXISTS created_by VARCHAR(64);
+ALTER TABLE user_tbl ADD COLUMN IF NOT EXISTS updated_by VARCHAR(64);
+ALTER TABLE user_tbl ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE user_tbl ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+CREATE INDEX IF NOT EXISTS idx_user_tbl_deleted_at ON user_tbl (deleted_at);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id               BIGSERIAL PRIMARY KEY,
+    aggregate_type   VARCHAR(128) NOT NULL,
+    aggregate_id     VARCHAR(128) NOT NULL,
+    event_type       VARCHAR(128) NOT NULL,
+    payload          TEXT NOT NULL,
+    headers          TEXT,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    attempts         INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_error       TEXT,
+    published_at     TIMESTAMPTZ,
+    failed_at        TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_aggregate ON outbox_events (aggregate_type, aggregate_id);
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending ON outbox_events (next_attempt_at) WHERE published_at IS NULL AND failed_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_published_at ON outbox_events (published_at);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_user_tbl_email;
+
+ALTER TABLE user_tbl DROP COLUMN IF EXISTS address;
+ALTER TABLE user_tbl DROP COLUMN IF EXISTS phone;
+ALTER TABLE user_tbl DROP COLUMN IF EXISTS age;
+ALTER TABLE user_tbl DROP COLUMN IF EXISTS password;
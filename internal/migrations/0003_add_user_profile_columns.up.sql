@@ -0,0 +1,6 @@
+ALTER TABLE user_tbl ADD COLUMN IF NOT EXISTS password VARCHAR(255);
+ALTER TABLE user_tbl ADD COLUMN IF NOT EXISTS age INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE user_tbl ADD COLUMN IF NOT EXISTS phone VARCHAR(50);
+ALTER TABLE user_tbl ADD COLUMN IF NOT EXISTS address VARCHAR(255);
+
+CREATE INDEX IF NOT EXISTS idx_user_tbl_email ON user_tbl (email);
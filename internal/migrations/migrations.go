@@ -0,0 +1,8 @@
+package migrations
+
+import "embed"
+
+// FS chứa các file migration của ứng dụng: <version>_<name>.up.sql / <version>_<name>.down.sql
+//
+//go:embed *.sql
+var FS embed.FS
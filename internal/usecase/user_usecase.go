@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"github.com/google/uuid"
 	"github.io/xhkzeroone/goframex/internal/domain"
 	"github.io/xhkzeroone/goframex/pkg/logger/logrusx"
 )
@@ -10,10 +11,12 @@ import (
 type userUsecase struct {
 	repo    domain.UserRepository
 	service domain.UserService
+	tx      domain.Transactor
+	events  domain.EventPublisher
 }
 
-func NewUserUsecase(repo domain.UserRepository, svc domain.UserService) domain.UserUsecase {
-	return &userUsecase{repo: repo, service: svc}
+func NewUserUsecase(repo domain.UserRepository, svc domain.UserService, tx domain.Transactor, events domain.EventPublisher) domain.UserUsecase {
+	return &userUsecase{repo: repo, service: svc, tx: tx, events: events}
 }
 
 func (u *userUsecase) CreateUser(ctx context.Context, user *domain.User) error {
@@ -36,9 +39,19 @@ func (u *userUsecase) CreateUser(ctx context.Context, user *domain.User) error {
 		return err
 	}
 	user.Password = hashedPassword
+	// ID cần có trước khi ghi để làm aggregate id của event
+	if user.ID == "" {
+		user.ID = uuid.NewString()
+	}
 
-	// Create user
-	if err := u.repo.Create(ctx, user); err != nil {
+	// Create user và ghi event user.created trong cùng transaction
+	err = u.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := u.repo.Create(ctx, user); err != nil {
+			return err
+		}
+		return u.events.Publish(ctx, domain.AggregateUser, user.ID, domain.EventUserCreated, user)
+	})
+	if err != nil {
 		logrusx.Log.Errorf("Failed to create user: %v", err)
 		return err
 	}
@@ -1,57 +1,185 @@
 package ymlx
 
 import (
+	"context"
 	"fmt"
-	"github.com/spf13/viper"
 	"log"
+	"maps"
 	"os"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
 )
 
-func Load(cfg interface{}) error {
+// Config là một nguồn cấu hình độc lập (mỗi instance có viper riêng),
+// cho phép nhiều config cùng tồn tại trong một process
+type Config struct {
+	mu sync.RWMutex
+	v  *viper.Viper
+
+	// opt là nguồn file để đọc lại khi file thay đổi, nil với NewFromMap
+	opt       *options
+	watchOnce sync.Once
+	reloadMu  sync.Mutex
+	listeners []func()
+}
+
+type Option func(o *options)
+
+type options struct {
+	dir string
+	env string
+}
+
+// WithDir đổi thư mục chứa config.yml (mặc định ./config)
+func WithDir(dir string) Option {
+	return func(o *options) {
+		o.dir = dir
+	}
+}
+
+// WithEnv chọn file config-<env>.yml để merge (mặc định lấy từ APP_ENV)
+func WithEnv(env string) Option {
+	return func(o *options) {
+		o.env = env
+	}
+}
+
+// New đọc config.yml, merge config-<env>.yml rồi resolve biến môi trường và secret
+func New(opts ...Option) (*Config, error) {
+	dir, _ := os.Getwd()
+	opt := &options{
+		dir: filepath.Join(dir, "./config"),
+		env: os.Getenv("APP_ENV"),
+	}
+	for _, o := range opts {
+		o(opt)
+	}
+
+	v, err := readFiles(opt)
+	if err != nil {
+		return nil, err
+	}
+	c, err := newConfig(v)
+	if err != nil {
+		return nil, err
+	}
+	c.opt = opt
+	return c, nil
+}
+
+func readFiles(opt *options) (*viper.Viper, error) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	v.AddConfigPath(opt.dir)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("can not load %s: %w", filepath.Join(opt.dir, "config.yml"), err)
+	}
+
+	if env := opt.envName(); env != "" {
+		v.SetConfigName("config-" + env)
+		if err := v.MergeInConfig(); err != nil {
+			log.Printf("Can not merge config-%s.yml: %v", env, err)
+		}
+	}
+	return v, nil
+}
+
+func (o *options) envName() string {
+	return strings.ToLower(o.env)
+}
+
+// NewFromMap tạo Config từ map trong bộ nhớ, chủ yếu dùng cho test.
+// Key lồng nhau có thể viết dạng "a.b.c" hoặc map lồng map, key cha được gán trước key con
+func NewFromMap(values map[string]any) (*Config, error) {
+	v := viper.New()
+	for _, k := range slices.Sorted(maps.Keys(values)) {
+		v.Set(k, values[k])
+	}
+	return newConfig(v)
+}
+
+func newConfig(v *viper.Viper) (*Config, error) {
+	if err := resolveViper(v); err != nil {
+		return nil, err
+	}
+	return &Config{v: v}, nil
+}
+
+func resolveViper(v *viper.Viper) error {
+	resolveEnvInViper(v)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := resolveSecretsInViper(ctx, v); err != nil {
+		return fmt.Errorf("can not resolve config secrets: %w", err)
+	}
+	return nil
+}
+
+func (c *Config) viper() *viper.Viper {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.v
+}
+
+// Unmarshal đổ cấu hình vào struct cfg
+func (c *Config) Unmarshal(cfg interface{}) error {
 	v := reflect.ValueOf(cfg)
 	if v.Kind() != reflect.Ptr || v.IsNil() {
 		return fmt.Errorf("cfg must be a non-nil pointer to a struct")
 	}
+	return c.viper().Unmarshal(cfg)
+}
 
-	loadFile()
+// Lookup trả về giá trị chuỗi của key và cho biết key có tồn tại hay không
+func (c *Config) Lookup(key string) (string, bool) {
+	v := c.viper()
+	if !v.IsSet(key) {
+		return "", false
+	}
+	return v.GetString(key), true
+}
+
+func (c *Config) GetString(key string) string {
+	return c.viper().GetString(key)
+}
+
+func (c *Config) IsSet(key string) bool {
+	return c.viper().IsSet(key)
+}
+
+// Viper trả về instance viper bên dưới, sau mỗi lần file thay đổi (OnChange) là một instance mới
+func (c *Config) Viper() *viper.Viper {
+	return c.viper()
+}
 
-	if err := viper.Unmarshal(cfg); err != nil {
+// Load đọc config từ thư mục mặc định và đổ vào struct cfg
+func Load(cfg interface{}) error {
+	c, err := New()
+	if err != nil {
+		log.Printf("Can not load config: %v", err)
+		return err
+	}
+	if err := c.Unmarshal(cfg); err != nil {
 		log.Printf("Can not unmarshal config into struct: %v", err)
 		return err
 	}
 	return nil
 }
 
-func resolveEnvInViper() {
-	settings := viper.AllSettings()
+func resolveEnvInViper(v *viper.Viper) {
+	settings := v.AllSettings()
 	for key, value := range settings {
 		if strVal, ok := value.(string); ok {
 			if envVal, exists := os.LookupEnv(strVal); exists {
-				viper.Set(key, envVal)
+				v.Set(key, envVal)
 			}
 		}
 	}
 }
-
-func loadFile() {
-	dir, _ := os.Getwd()
-	configPath := filepath.Join(dir, "./config")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath(configPath)
-	if err := viper.ReadInConfig(); err != nil {
-		log.Fatalf("Can not load config.yml: %v", filepath.Join(configPath, "config.yml"))
-	}
-
-	env := strings.ToLower(os.Getenv("APP_ENV"))
-	if env != "" {
-		viper.SetConfigName("config-" + env)
-		err := viper.MergeInConfig()
-		if err != nil {
-			return
-		}
-	}
-	resolveEnvInViper()
-}
@@ -0,0 +1,90 @@
+package ymlx
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type appConfig struct {
+	Server struct {
+		Port    int           `mapstructure:"port"`
+		Timeout time.Duration `mapstructure:"timeout"`
+	} `mapstructure:"server"`
+	Database struct {
+		Password string `mapstructure:"password"`
+	} `mapstructure:"database"`
+	Cron string `mapstructure:"cron"`
+}
+
+func TestNewFromMap(t *testing.T) {
+	t.Setenv("YMLX_TEST_CRON", "0 */5 * * * *")
+	t.Setenv("YMLX_TEST_PASSWORD", "s3cret-value")
+	ClearSecretCache()
+	t.Cleanup(ClearSecretCache)
+
+	c, err := NewFromMap(map[string]any{
+		"server":            map[string]any{"port": 8080},
+		"server.timeout":    "3s",
+		"database.password": "secret://env/YMLX_TEST_PASSWORD",
+		"cron":              "YMLX_TEST_CRON",
+	})
+	if err != nil {
+		t.Fatalf("new from map: %v", err)
+	}
+
+	var cfg appConfig
+	if err := c.Unmarshal(&cfg); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if cfg.Server.Port != 8080 || cfg.Server.Timeout != 3*time.Second {
+		t.Errorf("server = %+v", cfg.Server)
+	}
+	// Giá trị trùng tên biến môi trường và secret:// đều được resolve
+	if cfg.Cron != "0 */5 * * * *" || cfg.Database.Password != "s3cret-value" {
+		t.Errorf("cron = %q, password = %q", cfg.Cron, cfg.Database.Password)
+	}
+
+	if v, ok := c.Lookup("server.port"); !ok || v != "8080" {
+		t.Errorf("lookup server.port = %q, %v", v, ok)
+	}
+	if _, ok := c.Lookup("server.missing"); ok {
+		t.Error("lookup of a missing key reported ok")
+	}
+	if err := c.Unmarshal(cfg); err == nil {
+		t.Error("unmarshal into a non-pointer succeeded")
+	}
+}
+
+func TestNewFromMapUnresolvedSecret(t *testing.T) {
+	if _, err := NewFromMap(map[string]any{"db.password": "secret://nope/x"}); err == nil {
+		t.Fatal("expected an error for an unknown secret provider")
+	}
+}
+
+func TestNewMergesEnvFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("config.yml", "server:\n  port: 8080\n  timeout: 5s\n")
+	write("config-staging.yml", "server:\n  port: 9090\n")
+
+	c, err := New(WithDir(dir), WithEnv("Staging"))
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if got := c.GetString("server.port"); got != "9090" {
+		t.Errorf("server.port = %q, want 9090 from config-staging.yml", got)
+	}
+	if got := c.GetString("server.timeout"); got != "5s" {
+		t.Errorf("server.timeout = %q, want 5s from config.yml", got)
+	}
+
+	if _, err := New(WithDir(filepath.Join(dir, "missing"))); err == nil {
+		t.Error("expected an error when config.yml is missing")
+	}
+}
@@ -0,0 +1,143 @@
+package ymlx
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.io/xhkzeroone/goframex/pkg/logger/logrusx"
+)
+
+const maskedValue = "******"
+
+// Các tên field (sau khi bỏ "_" và "-", viết thường) được coi là bí mật khi dump
+var sensitiveNames = []string{"password", "secret", "token", "apikey", "credential", "authorization", "privatekey"}
+
+// Dump chuyển struct cấu hình (đã load) thành map theo tên key trong YAML, các giá trị bí mật được che.
+// Field được coi là bí mật khi có tag `secret:"true"`, tên chứa password/secret/token...,
+// hoặc giá trị đã được resolve qua secret://
+func Dump(cfg any) (map[string]any, error) {
+	v := reflect.ValueOf(cfg)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, fmt.Errorf("cfg must be a non-nil pointer to a struct")
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cfg must be a struct, got %s", v.Kind())
+	}
+	out, _ := dumpValue(v, false).(map[string]any)
+	return out, nil
+}
+
+func dumpValue(v reflect.Value, sensitive bool) any {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		return maskIf(sensitive, v.Interface().(time.Duration).String())
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		out := make(map[string]any)
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name, squash := fieldKey(f)
+			if name == "-" {
+				continue
+			}
+			child := dumpValue(v.Field(i), sensitive || isSensitiveField(f))
+			if squash {
+				if m, ok := child.(map[string]any); ok {
+					for k, val := range m {
+						out[k] = val
+					}
+				}
+				continue
+			}
+			out[name] = child
+		}
+		return out
+	case reflect.Map:
+		out := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			out[key] = dumpValue(iter.Value(), sensitive || isSensitiveName(key))
+		}
+		return out
+	case reflect.Slice, reflect.Array:
+		out := make([]any, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out[i] = dumpValue(v.Index(i), sensitive)
+		}
+		return out
+	case reflect.String:
+		s := v.String()
+		if s == "" {
+			return s
+		}
+		if sensitive {
+			return maskedValue
+		}
+		return logrusx.RedactSecrets(s)
+	default:
+		return maskIf(sensitive, v.Interface())
+	}
+}
+
+func maskIf(sensitive bool, val any) any {
+	if sensitive {
+		return maskedValue
+	}
+	return val
+}
+
+// fieldKey trả về tên key theo tag mapstructure (rồi tới yaml), và cho biết field có được squash không
+func fieldKey(f reflect.StructField) (string, bool) {
+	for _, tagName := range []string{"mapstructure", "yaml"} {
+		tag, ok := f.Tag.Lookup(tagName)
+		if !ok {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		squash := strings.Contains(opts, "squash") || strings.Contains(opts, "inline")
+		if name == "" && !squash {
+			name = strings.ToLower(f.Name)
+		}
+		return name, squash
+	}
+	if f.Anonymous {
+		return "", true
+	}
+	return strings.ToLower(f.Name), false
+}
+
+func isSensitiveField(f reflect.StructField) bool {
+	if f.Tag.Get("secret") == "true" {
+		return true
+	}
+	name, _ := fieldKey(f)
+	return isSensitiveName(f.Name) || isSensitiveName(name)
+}
+
+func isSensitiveName(name string) bool {
+	n := strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+	for _, s := range sensitiveNames {
+		if strings.Contains(n, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package ymlx
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.io/xhkzeroone/goframex/pkg/logger/logrusx"
+)
+
+type DumpCommon struct {
+	Region string `mapstructure:"region"`
+}
+
+type dumpNode struct {
+	Name     string      `mapstructure:"name"`
+	Children []*dumpNode `mapstructure:"children"`
+}
+
+type dumpConfig struct {
+	DumpCommon `mapstructure:",squash"`
+	Server     struct {
+		Port    int           `mapstructure:"port" description:"listen port"`
+		Timeout time.Duration `mapstructure:"timeout"`
+	} `mapstructure:"server"`
+	Database struct {
+		DSN      string `mapstructure:"dsn" secret:"true"`
+		Password string `mapstructure:"password"`
+		APIKey   string `mapstructure:"api_key"`
+		Host     string `mapstructure:"host"`
+	} `mapstructure:"database"`
+	Headers map[string]string `mapstructure:"headers"`
+	Tags    []string          `mapstructure:"tags"`
+	Tree    *dumpNode         `mapstructure:"tree"`
+	Ignored string            `mapstructure:"-"`
+	hidden  string
+}
+
+func TestDumpMasksSecrets(t *testing.T) {
+	logrusx.MarkSecret("resolved-from-vault")
+	cfg := &dumpConfig{Headers: map[string]string{"Authorization": "Bearer x", "Accept": "json"}, Tags: []string{"a"}}
+	cfg.Region = "ap-southeast-1"
+	cfg.Server.Port = 8080
+	cfg.Server.Timeout = 5 * time.Second
+	cfg.Database.DSN = "postgres://u:p@h/db"
+	cfg.Database.Password = "hunter2"
+	cfg.Database.APIKey = "k"
+	cfg.Database.Host = "resolved-from-vault"
+	cfg.Ignored = "x"
+	cfg.hidden = "x"
+
+	got, err := Dump(cfg)
+	if err != nil {
+		t.Fatalf("dump: %v", err)
+	}
+	want := map[string]any{
+		"region": "ap-southeast-1",
+		"server": map[string]any{"port": 8080, "timeout": "5s"},
+		"database": map[string]any{
+			"dsn": maskedValue, "password": maskedValue, "api_key": maskedValue, "host": maskedValue,
+		},
+		"headers": map[string]any{"Authorization": maskedValue, "Accept": "json"},
+		"tags":    []any{"a"},
+		"tree":    nil,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("dump = %#v\nwant %#v", got, want)
+	}
+
+	if _, err := Dump((*dumpConfig)(nil)); err == nil {
+		t.Error("dump of nil pointer succeeded")
+	}
+	if _, err := Dump(42); err == nil {
+		t.Error("dump of non-struct succeeded")
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	schema := JSONSchema(dumpConfig{}, SchemaStrict(), SchemaTitle("app"))
+	if schema["$schema"] != jsonSchemaDraft || schema["title"] != "app" || schema["additionalProperties"] != false {
+		t.Errorf("root = %v", schema)
+	}
+	props := schema["properties"].(map[string]any)
+	if _, ok := props["region"]; !ok {
+		t.Error("squashed field region missing from root properties")
+	}
+	for _, key := range []string{"Ignored", "-", "hidden", "DumpCommon", "dumpcommon"} {
+		if _, ok := props[key]; ok {
+			t.Errorf("unexpected property %q", key)
+		}
+	}
+
+	server := props["server"].(map[string]any)["properties"].(map[string]any)
+	if port := server["port"].(map[string]any); port["type"] != "integer" || port["description"] != "listen port" {
+		t.Errorf("server.port = %v", port)
+	}
+	if timeout := server["timeout"].(map[string]any); !reflect.DeepEqual(timeout["type"], []string{"string", "integer"}) {
+		t.Errorf("server.timeout = %v", timeout)
+	}
+	database := props["database"].(map[string]any)["properties"].(map[string]any)
+	if database["password"].(map[string]any)["writeOnly"] != true || database["host"].(map[string]any)["writeOnly"] != nil {
+		t.Errorf("database = %v", database)
+	}
+	if headers := props["headers"].(map[string]any); headers["additionalProperties"].(map[string]any)["type"] != "string" {
+		t.Errorf("headers = %v", headers)
+	}
+	// Kiểu đệ quy dừng ở lần lặp thứ hai
+	children := props["tree"].(map[string]any)["properties"].(map[string]any)["children"].(map[string]any)
+	if item := children["items"].(map[string]any); item["type"] != "object" || item["properties"] != nil {
+		t.Errorf("tree.children.items = %v", item)
+	}
+}
@@ -0,0 +1,133 @@
+package ymlx
+
+import (
+	"reflect"
+	"time"
+)
+
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+type SchemaOption func(o *schemaOptions)
+
+type schemaOptions struct {
+	strict bool
+	title  string
+}
+
+// SchemaStrict cấm các key không khai báo trong struct (additionalProperties: false),
+// giúp CI phát hiện key gõ sai trong config-*.yml
+func SchemaStrict() SchemaOption {
+	return func(o *schemaOptions) {
+		o.strict = true
+	}
+}
+
+func SchemaTitle(title string) SchemaOption {
+	return func(o *schemaOptions) {
+		o.title = title
+	}
+}
+
+// JSONSchema sinh JSON Schema cho file YAML tương ứng với struct cấu hình cfg,
+// dùng tên key theo tag mapstructure giống như khi load
+func JSONSchema(cfg any, opts ...SchemaOption) map[string]any {
+	opt := &schemaOptions{}
+	for _, o := range opts {
+		o(opt)
+	}
+
+	schema := typeSchema(reflect.TypeOf(cfg), opt, map[reflect.Type]bool{})
+	schema["$schema"] = jsonSchemaDraft
+	if opt.title != "" {
+		schema["title"] = opt.title
+	}
+	return schema
+}
+
+func typeSchema(t reflect.Type, opt *schemaOptions, visiting map[reflect.Type]bool) map[string]any {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == reflect.TypeOf(time.Duration(0)) {
+		return map[string]any{
+			"type":        []string{"string", "integer"},
+			"description": "duration, e.g. 30s, 5m, 1h",
+		}
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if visiting[t] {
+			return map[string]any{"type": "object"}
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		props := make(map[string]any)
+		collectProperties(t, props, opt, visiting)
+		schema := map[string]any{
+			"type":       "object",
+			"properties": props,
+		}
+		if opt.strict {
+			schema["additionalProperties"] = false
+		}
+		return schema
+	case reflect.Map:
+		return map[string]any{
+			"type":                 "object",
+			"additionalProperties": typeSchema(t.Elem(), opt, visiting),
+		}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{
+			"type":  "array",
+			"items": typeSchema(t.Elem(), opt, visiting),
+		}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	default:
+		return map[string]any{}
+	}
+}
+
+func collectProperties(t reflect.Type, props map[string]any, opt *schemaOptions, visiting map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, squash := fieldKey(f)
+		if name == "-" {
+			continue
+		}
+		if squash {
+			ft := f.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				collectProperties(ft, props, opt, visiting)
+			}
+			continue
+		}
+		fieldSchema := typeSchema(f.Type, opt, visiting)
+		if desc := f.Tag.Get("description"); desc != "" {
+			fieldSchema["description"] = desc
+		}
+		if isSensitiveField(f) {
+			fieldSchema["writeOnly"] = true
+		}
+		props[name] = fieldSchema
+	}
+}
@@ -0,0 +1,105 @@
+package ymlx
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// SecretScheme là tiền tố đánh dấu một giá trị config cần được resolve qua SecretProvider,
+// ví dụ: secret://file/run/secrets/db_password, secret://env/DB_PASSWORD, secret://vault/app/db#password
+const SecretScheme = "secret://"
+
+// SecretProvider resolve một tham chiếu bí mật (phần sau "secret://<provider>/") thành giá trị thật
+type SecretProvider interface {
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+// SecretProviderFunc cho phép dùng function làm SecretProvider
+type SecretProviderFunc func(ctx context.Context, ref string) (string, error)
+
+func (f SecretProviderFunc) Resolve(ctx context.Context, ref string) (string, error) {
+	return f(ctx, ref)
+}
+
+var (
+	providersMu sync.RWMutex
+	providers   = map[string]SecretProvider{
+		"file": &FileSecretProvider{},
+		"env":  &EnvSecretProvider{},
+	}
+)
+
+// RegisterSecretProvider đăng ký (hoặc ghi đè) provider cho tên cho trước
+func RegisterSecretProvider(name string, p SecretProvider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers[name] = p
+}
+
+func getSecretProvider(name string) (SecretProvider, bool) {
+	providersMu.RLock()
+	p, ok := providers[name]
+	providersMu.RUnlock()
+	if ok {
+		return p, true
+	}
+	// Vault được bật tự động khi có VAULT_ADDR trong môi trường
+	if name == "vault" {
+		if vp := NewVaultSecretProviderFromEnv(); vp != nil {
+			RegisterSecretProvider(name, vp)
+			return vp, true
+		}
+	}
+	return nil, false
+}
+
+// IsSecretRef kiểm tra giá trị có phải tham chiếu secret:// hay không
+func IsSecretRef(value string) bool {
+	return strings.HasPrefix(value, SecretScheme)
+}
+
+// parseSecretRef tách "secret://vault/app/db#password" thành ("vault", "app/db#password")
+func parseSecretRef(value string) (string, string, error) {
+	rest := strings.TrimPrefix(value, SecretScheme)
+	name, ref, ok := strings.Cut(rest, "/")
+	if !ok || name == "" || ref == "" {
+		return "", "", fmt.Errorf("invalid secret reference '%s'", value)
+	}
+	return name, ref, nil
+}
+
+// FileSecretProvider đọc secret từ file (Docker/Kubernetes secrets), bỏ ký tự xuống dòng cuối
+type FileSecretProvider struct {
+	// BaseDir dùng khi ref là đường dẫn tương đối
+	BaseDir string
+}
+
+func (p *FileSecretProvider) Resolve(_ context.Context, ref string) (string, error) {
+	path := ref
+	if p.BaseDir != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(p.BaseDir, path)
+	} else if !filepath.IsAbs(path) {
+		// secret://file/run/secrets/x => /run/secrets/x
+		path = "/" + path
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("can not read secret file %s: %w", path, err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// EnvSecretProvider đọc secret từ biến môi trường
+type EnvSecretProvider struct{}
+
+func (p *EnvSecretProvider) Resolve(_ context.Context, ref string) (string, error) {
+	val, ok := os.LookupEnv(ref)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", ref)
+	}
+	return val, nil
+}
@@ -0,0 +1,94 @@
+package ymlx
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.io/xhkzeroone/goframex/pkg/logger/logrusx"
+)
+
+// DefaultSecretCacheTTL là thời gian cache mặc định cho secret đã resolve
+const DefaultSecretCacheTTL = 5 * time.Minute
+
+type cachedSecret struct {
+	value     string
+	expiresAt time.Time
+}
+
+var (
+	secretCacheMu  sync.Mutex
+	secretCache    = make(map[string]cachedSecret)
+	secretCacheTTL = DefaultSecretCacheTTL
+)
+
+// SetSecretCacheTTL thay đổi TTL cache, ttl <= 0 sẽ tắt cache
+func SetSecretCacheTTL(ttl time.Duration) {
+	secretCacheMu.Lock()
+	defer secretCacheMu.Unlock()
+	secretCacheTTL = ttl
+}
+
+// ClearSecretCache xóa toàn bộ secret đã cache
+func ClearSecretCache() {
+	secretCacheMu.Lock()
+	defer secretCacheMu.Unlock()
+	secretCache = make(map[string]cachedSecret)
+}
+
+// ResolveSecret resolve giá trị dạng secret://<provider>/<ref>.
+// Giá trị không có tiền tố secret:// được trả về nguyên vẹn.
+// Secret đã resolve được đánh dấu để logrusx che đi khi ghi log
+func ResolveSecret(ctx context.Context, value string) (string, error) {
+	if !IsSecretRef(value) {
+		return value, nil
+	}
+
+	secretCacheMu.Lock()
+	if c, ok := secretCache[value]; ok && time.Now().Before(c.expiresAt) {
+		secretCacheMu.Unlock()
+		return c.value, nil
+	}
+	ttl := secretCacheTTL
+	secretCacheMu.Unlock()
+
+	name, ref, err := parseSecretRef(value)
+	if err != nil {
+		return "", err
+	}
+	provider, ok := getSecretProvider(name)
+	if !ok {
+		return "", fmt.Errorf("no secret provider registered for '%s'", name)
+	}
+	resolved, err := provider.Resolve(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("can not resolve secret %s: %w", value, err)
+	}
+
+	logrusx.MarkSecret(resolved)
+
+	if ttl > 0 {
+		secretCacheMu.Lock()
+		secretCache[value] = cachedSecret{value: resolved, expiresAt: time.Now().Add(ttl)}
+		secretCacheMu.Unlock()
+	}
+	return resolved, nil
+}
+
+// resolveSecretsInViper thay thế mọi giá trị secret:// trong viper bằng giá trị thật
+func resolveSecretsInViper(ctx context.Context, v *viper.Viper) error {
+	for _, key := range v.AllKeys() {
+		strVal, ok := v.Get(key).(string)
+		if !ok || !IsSecretRef(strVal) {
+			continue
+		}
+		resolved, err := ResolveSecret(ctx, strVal)
+		if err != nil {
+			return fmt.Errorf("config key %s: %w", key, err)
+		}
+		v.Set(key, resolved)
+	}
+	return nil
+}
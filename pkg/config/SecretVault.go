@@ -0,0 +1,92 @@
+package ymlx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// VaultSecretProvider đọc secret từ HTTP API tương thích Vault (KV v1 và v2).
+// Ref có dạng "<path>#<key>", ví dụ: secret/data/app/db#password
+type VaultSecretProvider struct {
+	Address    string
+	Token      string
+	Namespace  string
+	HTTPClient *http.Client
+}
+
+func NewVaultSecretProvider(address, token string) *VaultSecretProvider {
+	return &VaultSecretProvider{
+		Address:    strings.TrimRight(address, "/"),
+		Token:      token,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// NewVaultSecretProviderFromEnv khởi tạo provider từ VAULT_ADDR, VAULT_TOKEN, VAULT_NAMESPACE.
+// Trả về nil nếu VAULT_ADDR chưa được đặt
+func NewVaultSecretProviderFromEnv() *VaultSecretProvider {
+	addr := os.Getenv("VAULT_ADDR")
+	if addr == "" {
+		return nil
+	}
+	p := NewVaultSecretProvider(addr, os.Getenv("VAULT_TOKEN"))
+	p.Namespace = os.Getenv("VAULT_NAMESPACE")
+	return p
+}
+
+type vaultResponse struct {
+	Data map[string]any `json:"data"`
+}
+
+func (p *VaultSecretProvider) Resolve(ctx context.Context, ref string) (string, error) {
+	path, key, ok := strings.Cut(ref, "#")
+	if !ok || key == "" {
+		return "", fmt.Errorf("vault secret reference '%s' must have the form path#key", ref)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.Address+"/v1/"+strings.TrimLeft(path, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	if p.Token != "" {
+		req.Header.Set("X-Vault-Token", p.Token)
+	}
+	if p.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.Namespace)
+	}
+
+	client := p.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault request for %s failed with status: %s", path, resp.Status)
+	}
+
+	var body vaultResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("can not decode vault response: %w", err)
+	}
+
+	data := body.Data
+	// KV v2 bọc dữ liệu trong data.data
+	if nested, ok := data["data"].(map[string]any); ok {
+		data = nested
+	}
+	val, ok := data[key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found in vault secret %s", key, path)
+	}
+	return fmt.Sprint(val), nil
+}
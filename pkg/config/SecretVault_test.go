@@ -0,0 +1,100 @@
+package ymlx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// vaultStub trả về secret theo path, kiểm tra header token/namespace như Vault
+func vaultStub(t *testing.T, secrets map[string]string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "root" {
+			http.Error(w, `{"errors":["permission denied"]}`, http.StatusForbidden)
+			return
+		}
+		if ns := r.Header.Get("X-Vault-Namespace"); ns != "" && ns != "team" {
+			http.Error(w, `{"errors":["no handler for route"]}`, http.StatusNotFound)
+			return
+		}
+		body, ok := secrets[r.URL.Path]
+		if !ok {
+			http.Error(w, `{"errors":[]}`, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestVaultSecretProvider(t *testing.T) {
+	srv := vaultStub(t, map[string]string{
+		"/v1/secret/data/app/db": `{"data":{"data":{"password":"kv2-pass","port":5432},"metadata":{"version":3}}}`,
+		"/v1/kv/app/db":          `{"data":{"password":"kv1-pass"}}`,
+		"/v1/secret/data/broken": `{"data":`,
+	})
+
+	tests := []struct {
+		name      string
+		token     string
+		namespace string
+		ref       string
+		want      string
+		wantErr   string
+	}{
+		{name: "kv v2", token: "root", ref: "secret/data/app/db#password", want: "kv2-pass"},
+		{name: "kv v2 number", token: "root", ref: "/secret/data/app/db#port", want: "5432"},
+		{name: "kv v1", token: "root", ref: "kv/app/db#password", want: "kv1-pass"},
+		{name: "namespace", token: "root", namespace: "team", ref: "kv/app/db#password", want: "kv1-pass"},
+		{name: "missing key", token: "root", ref: "kv/app/db#user", wantErr: "key user not found"},
+		{name: "missing path", token: "root", ref: "kv/other#password", wantErr: "404"},
+		{name: "bad token", token: "guest", ref: "kv/app/db#password", wantErr: "403"},
+		{name: "bad body", token: "root", ref: "secret/data/broken#password", wantErr: "decode"},
+		{name: "bad ref", token: "root", ref: "kv/app/db", wantErr: "path#key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewVaultSecretProvider(srv.URL+"/", tt.token)
+			p.Namespace = tt.namespace
+			got, err := p.Resolve(context.Background(), tt.ref)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("got %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVaultSecretProviderFromEnv(t *testing.T) {
+	srv := vaultStub(t, map[string]string{
+		"/v1/kv/app/db": `{"data":{"password":"from-env"}}`,
+	})
+	t.Setenv("VAULT_ADDR", srv.URL)
+	t.Setenv("VAULT_TOKEN", "root")
+	t.Setenv("VAULT_NAMESPACE", "team")
+	ClearSecretCache()
+	providersMu.Lock()
+	delete(providers, "vault")
+	providersMu.Unlock()
+	t.Cleanup(func() {
+		providersMu.Lock()
+		delete(providers, "vault")
+		providersMu.Unlock()
+		ClearSecretCache()
+	})
+
+	got, err := ResolveSecret(context.Background(), "secret://vault/kv/app/db#password")
+	if err != nil || got != "from-env" {
+		t.Fatalf("got %q, %v; want from-env", got, err)
+	}
+}
@@ -0,0 +1,69 @@
+package ymlx
+
+import (
+	"log"
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// OnChange gọi fn mỗi khi config.yml hoặc config-<env>.yml thay đổi trên đĩa, ví dụ để gọi cronx.Cron.Reload.
+// Config được đọc lại từ đầu (kể cả biến môi trường và secret) trước khi gọi fn, đọc lỗi thì giữ giá trị cũ.
+// Lần gọi đầu tiên bắt đầu theo dõi file. Config tạo bằng NewFromMap không có file nên fn không bao giờ được gọi
+func (c *Config) OnChange(fn func()) {
+	c.reloadMu.Lock()
+	c.listeners = append(c.listeners, fn)
+	c.reloadMu.Unlock()
+
+	if c.opt == nil {
+		return
+	}
+	c.watchOnce.Do(func() {
+		c.watch("config")
+		if env := c.opt.envName(); env != "" {
+			c.watch("config-" + env)
+		}
+	})
+}
+
+// watch theo dõi một file config bằng viper.WatchConfig, file không tồn tại thì bỏ qua
+func (c *Config) watch(name string) {
+	w := viper.New()
+	w.SetConfigType("yaml")
+	w.SetConfigName(name)
+	w.AddConfigPath(c.opt.dir)
+	if err := w.ReadInConfig(); err != nil {
+		return
+	}
+	w.OnConfigChange(func(e fsnotify.Event) {
+		if err := c.Reload(); err != nil {
+			log.Printf("Can not reload %s: %v", filepath.Base(e.Name), err)
+		}
+	})
+	w.WatchConfig()
+}
+
+// Reload đọc lại file config rồi gọi các hàm đã đăng ký bằng OnChange, lỗi thì giữ giá trị cũ.
+// Config tạo bằng NewFromMap không có file nên chỉ gọi các hàm đã đăng ký
+func (c *Config) Reload() error {
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+
+	if c.opt != nil {
+		v, err := readFiles(c.opt)
+		if err != nil {
+			return err
+		}
+		if err := resolveViper(v); err != nil {
+			return err
+		}
+		c.mu.Lock()
+		c.v = v
+		c.mu.Unlock()
+	}
+	for _, fn := range c.listeners {
+		fn()
+	}
+	return nil
+}
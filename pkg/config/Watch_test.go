@@ -0,0 +1,77 @@
+package ymlx
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOnChangeReloadsFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("WATCH_TEST_TOKEN", "from-env")
+	write("config.yml", "token: WATCH_TEST_TOKEN\ncron: \"0 0 3 * * *\"\n")
+	write("config-staging.yml", "server:\n  port: 9090\n")
+
+	c, err := New(WithDir(dir), WithEnv("staging"))
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	changed := make(chan struct{}, 10)
+	c.OnChange(func() { changed <- struct{}{} })
+	wait := func(key, want string) {
+		t.Helper()
+		deadline := time.After(5 * time.Second)
+		for c.GetString(key) != want {
+			select {
+			case <-changed:
+			case <-deadline:
+				t.Fatalf("%s = %q after change, want %q", key, c.GetString(key), want)
+			}
+		}
+	}
+
+	write("config.yml", "token: WATCH_TEST_TOKEN\ncron: \"0 30 4 * * *\"\n")
+	wait("cron", "0 30 4 * * *")
+	if got := c.GetString("token"); got != "from-env" {
+		t.Errorf("token = %q after reload, want env value", got)
+	}
+	if got := c.GetString("server.port"); got != "9090" {
+		t.Errorf("server.port = %q after reload, want merged env file", got)
+	}
+
+	write("config-staging.yml", "server:\n  port: 9191\n")
+	wait("server.port", "9191")
+}
+
+func TestReloadKeepsValuesOnError(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yml")
+	if err := os.WriteFile(path, []byte("cron: \"@every 1m\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := New(WithDir(dir), WithEnv(""))
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	calls := 0
+	c.OnChange(func() { calls++ })
+
+	if err := os.WriteFile(path, []byte("cron: [unterminated\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Reload(); err == nil {
+		t.Fatal("expected an error for invalid yaml")
+	}
+	if got := c.GetString("cron"); got != "@every 1m" {
+		t.Errorf("cron = %q after failed reload, want previous value", got)
+	}
+	if calls != 0 {
+		t.Errorf("listeners called %d times after failed reload", calls)
+	}
+}
@@ -0,0 +1,182 @@
+package gormx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// ErrEmptyFilter trả về khi thao tác ghi hàng loạt không có điều kiện lọc
+var ErrEmptyFilter = errors.New("gormx: bulk write requires a non-empty filter")
+
+const defaultBatchSize = 500
+
+// InsertBatch thêm nhiều entity, chia thành từng lô batchSize bản ghi (<= 0 dùng mặc định 500)
+func (r *Repository[T, ID]) InsertBatch(ctx context.Context, items []T, batchSize int) error {
+	if len(items) == 0 {
+		return nil
+	}
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	return r.Writer(ctx).Model(new(T)).CreateInBatches(&items, batchSize).Error
+}
+
+// Upsert thêm hoặc cập nhật khi trùng conflictColumns (ON CONFLICT / ON DUPLICATE KEY).
+// updateColumns rỗng sẽ cập nhật toàn bộ cột không phải khóa chính
+func (r *Repository[T, ID]) Upsert(ctx context.Context, items []T, conflictColumns []string, updateColumns []string) error {
+	if len(items) == 0 {
+		return nil
+	}
+	sch, err := r.schema()
+	if err != nil {
+		return err
+	}
+
+	onConflict := clause.OnConflict{}
+	for _, name := range conflictColumns {
+		col, err := ResolveColumn(sch, name)
+		if err != nil {
+			return err
+		}
+		onConflict.Columns = append(onConflict.Columns, clause.Column{Name: col.Name})
+	}
+	if len(updateColumns) == 0 {
+		onConflict.UpdateAll = true
+	} else {
+		names := make([]string, 0, len(updateColumns))
+		for _, name := range updateColumns {
+			col, err := ResolveColumn(sch, name)
+			if err != nil {
+				return err
+			}
+			names = append(names, col.Name)
+		}
+		onConflict.DoUpdates = clause.AssignmentColumns(names)
+	}
+
+	return r.Writer(ctx).Model(new(T)).Clauses(onConflict).CreateInBatches(&items, defaultBatchSize).Error
+}
+
+// UpdateWhere cập nhật các cột trong values cho mọi bản ghi thỏa spec, trả về số dòng bị ảnh hưởng.
+// Từ chối spec không có điều kiện lọc
+func (r *Repository[T, ID]) UpdateWhere(ctx context.Context, spec *Spec, values map[string]any) (int64, error) {
+	if !spec.HasFilter() {
+		return 0, ErrEmptyFilter
+	}
+	if len(values) == 0 {
+		return 0, nil
+	}
+	sch, err := r.schema()
+	if err != nil {
+		return 0, err
+	}
+	updates, err := resolveColumnMap(sch, values)
+	if err != nil {
+		return 0, err
+	}
+
+	// Entity Versioned luôn tăng version để các Update đang giữ bản cũ bị từ chối
+	if _, ok := any(new(T)).(versioned); ok {
+		updates["version"] = gorm.Expr("? + 1", clause.Column{Table: clause.CurrentTable, Name: "version"})
+	}
+
+	where, err := bulkFilter(spec, sch)
+	if err != nil {
+		return 0, err
+	}
+	res := r.Writer(ctx).Model(new(T)).Clauses(where).Updates(updates)
+	return res.RowsAffected, res.Error
+}
+
+// DeleteWhere xóa mọi bản ghi thỏa spec, trả về số dòng bị xóa. Từ chối spec không có điều kiện lọc
+func (r *Repository[T, ID]) DeleteWhere(ctx context.Context, spec *Spec) (int64, error) {
+	if !spec.HasFilter() {
+		return 0, ErrEmptyFilter
+	}
+	sch, err := r.schema()
+	if err != nil {
+		return 0, err
+	}
+	where, err := bulkFilter(spec, sch)
+	if err != nil {
+		return 0, err
+	}
+	res := r.Writer(ctx).Model(new(T)).Clauses(where).Delete(new(T))
+	return res.RowsAffected, res.Error
+}
+
+// bulkFilter build điều kiện của spec, trả về ErrEmptyFilter khi không còn điều kiện nào
+// (Condition tự cài đặt cũng có thể build ra nil)
+func bulkFilter(spec *Spec, sch *schema.Schema) (clause.Where, error) {
+	exprs, err := buildConditions(spec.conds, sch)
+	if err != nil {
+		return clause.Where{}, err
+	}
+	if len(exprs) == 0 {
+		return clause.Where{}, ErrEmptyFilter
+	}
+	return clause.Where{Exprs: exprs}, nil
+}
+
+type StreamOption func(o *streamOptions)
+
+type streamOptions struct {
+	batchSize int
+}
+
+// WithBatchSize đổi số bản ghi mỗi lô khi Stream (mặc định 500)
+func WithBatchSize(size int) StreamOption {
+	return func(o *streamOptions) {
+		o.batchSize = size
+	}
+}
+
+// Stream duyệt toàn bộ bản ghi thỏa spec theo từng lô bằng phân trang keyset,
+// không nạp tất cả vào bộ nhớ. fn trả về lỗi sẽ dừng việc duyệt
+func (r *Repository[T, ID]) Stream(ctx context.Context, spec *Spec, fn func(batch []T) error, opts ...StreamOption) error {
+	opt := &streamOptions{batchSize: defaultBatchSize}
+	for _, o := range opts {
+		o(opt)
+	}
+	if opt.batchSize <= 0 {
+		opt.batchSize = defaultBatchSize
+	}
+
+	after := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		page, err := r.Cursor(ctx, spec, after, opt.batchSize)
+		if err != nil {
+			return err
+		}
+		if len(page.Items) > 0 {
+			if err := fn(page.Items); err != nil {
+				return err
+			}
+		}
+		if !page.HasNext {
+			return nil
+		}
+		after = page.NextCursor
+	}
+}
+
+// resolveColumnMap đổi key (tên cột hoặc tên field) sang tên cột DB đã được kiểm tra
+func resolveColumnMap(sch *schema.Schema, values map[string]any) (map[string]any, error) {
+	out := make(map[string]any, len(values))
+	for name, v := range values {
+		col, err := ResolveColumn(sch, name)
+		if err != nil {
+			return nil, fmt.Errorf("update %w", err)
+		}
+		out[col.Name] = v
+	}
+	return out, nil
+}
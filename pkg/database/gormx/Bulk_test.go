@@ -0,0 +1,91 @@
+package gormx_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.io/xhkzeroone/goframex/pkg/database/gormx"
+	"github.io/xhkzeroone/goframex/pkg/database/gormx/gormxtest"
+)
+
+func TestUpsert(t *testing.T) {
+	ctx, repo := seedProducts(t)
+
+	items := []product{
+		{ID: 10, Name: "apple", Category: "imported", Price: 35},
+		{ID: 6, Name: "fig", Category: "fruit", Price: 40},
+	}
+	if err := repo.Upsert(ctx, items, []string{"name"}, []string{"Price"}); err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+
+	apple, err := repo.FindOne(ctx, gormx.NewSpec(gormx.Eq("name", "apple")))
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	// Chỉ cột trong updateColumns bị ghi đè khi trùng
+	if apple.ID != 1 || apple.Price != 35 || apple.Category != "fruit" {
+		t.Fatalf("apple = %+v", apple)
+	}
+	gormxtest.AssertRowCount(t, ctx, repo.DataSource, &product{}, 6)
+}
+
+func TestUpdateAndDeleteWhere(t *testing.T) {
+	ctx, repo := seedProducts(t)
+	vegetables := gormx.NewSpec(gormx.Eq("category", "vegetable"))
+
+	n, err := repo.UpdateWhere(ctx, vegetables, map[string]any{"Price": 25})
+	if err != nil || n != 2 {
+		t.Fatalf("update where = %d, %v; want 2", n, err)
+	}
+	gormxtest.AssertRowCount(t, ctx, repo.DataSource, &product{}, 2, "price = ?", 25)
+
+	n, err = repo.DeleteWhere(ctx, vegetables)
+	if err != nil || n != 2 {
+		t.Fatalf("delete where = %d, %v; want 2", n, err)
+	}
+	gormxtest.AssertNotExists(t, ctx, repo.DataSource, &product{}, "category = ?", "vegetable")
+}
+
+func TestBulkWriteRequiresFilter(t *testing.T) {
+	ctx, repo := seedProducts(t)
+
+	if _, err := repo.UpdateWhere(ctx, gormx.NewSpec(), map[string]any{"price": 0}); !errors.Is(err, gormx.ErrEmptyFilter) {
+		t.Fatalf("update where err = %v, want ErrEmptyFilter", err)
+	}
+	if _, err := repo.DeleteWhere(ctx, nil); !errors.Is(err, gormx.ErrEmptyFilter) {
+		t.Fatalf("delete where err = %v, want ErrEmptyFilter", err)
+	}
+	if _, err := repo.UpdateWhere(ctx, gormx.NewSpec(gormx.Eq("id", 1)), map[string]any{"missing": 0}); !errors.Is(err, gormx.ErrUnknownColumn) {
+		t.Fatalf("update where err = %v, want ErrUnknownColumn", err)
+	}
+	gormxtest.AssertRowCount(t, ctx, repo.DataSource, &product{}, 5)
+}
+
+func TestStream(t *testing.T) {
+	ctx, repo := seedProducts(t)
+
+	var batches [][]string
+	err := repo.Stream(ctx, gormx.NewSpec(gormx.Gt("price", 10)).OrderBy(gormx.Asc("name")), func(batch []product) error {
+		batches = append(batches, names(batch))
+		return nil
+	}, gormx.WithBatchSize(3))
+	if err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+	want := [][]string{{"apple", "carrot", "durian"}, {"eggplant"}}
+	if !slices.EqualFunc(batches, want, slices.Equal[[]string]) {
+		t.Fatalf("batches = %v, want %v", batches, want)
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err = repo.Stream(ctx, nil, func([]product) error {
+		calls++
+		return stop
+	}, gormx.WithBatchSize(1))
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Fatalf("stream err = %v after %d calls, want stop after 1", err, calls)
+	}
+}
@@ -0,0 +1,349 @@
+package gormx
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"reflect"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm/schema"
+)
+
+// Cache là nơi CachedRepository lưu entity, xem NewRedisCache và NewLRUCache
+type Cache interface {
+	// Get trả về false khi key không tồn tại hoặc đã hết hạn
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set với ttl <= 0 là không hết hạn
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+type CacheOption func(o *cacheOptions)
+
+type cacheOptions struct {
+	ttl     time.Duration
+	prefix  string
+	version string
+}
+
+// WithCacheTTL thời gian sống của entity trong cache (mặc định 10 phút)
+func WithCacheTTL(ttl time.Duration) CacheOption {
+	return func(o *cacheOptions) {
+		o.ttl = ttl
+	}
+}
+
+// WithCachePrefix đổi tiền tố key (mặc định "gormx:<tên bảng>")
+func WithCachePrefix(prefix string) CacheOption {
+	return func(o *cacheOptions) {
+		o.prefix = prefix
+	}
+}
+
+// WithCacheVersion gắn version vào key, tăng version khi đổi cách mã hóa entity để không đọc lại blob cũ.
+// Thay đổi cột của entity đã tự đổi key nên không cần tăng version
+func WithCacheVersion(version string) CacheOption {
+	return func(o *cacheOptions) {
+		o.version = version
+	}
+}
+
+// CachedRepository bọc Repository, cache kết quả FindByID và xóa cache khi ghi qua repository.
+// Việc xóa cache chỉ diễn ra sau khi transaction commit; đọc trong transaction không dùng cache.
+// Ghi trực tiếp bằng Writer/DB (không qua repository) sẽ không xóa cache, entry cũ tồn tại tới hết TTL
+type CachedRepository[T any, ID comparable] struct {
+	*Repository[T, ID]
+	cache  Cache
+	ttl    time.Duration
+	prefix string
+}
+
+// NewCachedRepository tạo cache decorator cho repo, vẫn thỏa IRepository
+func NewCachedRepository[T any, ID comparable](repo *Repository[T, ID], cache Cache, opts ...CacheOption) *CachedRepository[T, ID] {
+	o := &cacheOptions{ttl: 10 * time.Minute, prefix: "gormx:" + repo.tableName()}
+	for _, opt := range opts {
+		opt(o)
+	}
+	prefix := o.prefix + ":" + repo.schemaFingerprint()
+	if o.version != "" {
+		prefix += ":v" + o.version
+	}
+	return &CachedRepository[T, ID]{Repository: repo, cache: cache, ttl: o.ttl, prefix: prefix}
+}
+
+// FindByID đọc từ cache, nếu chưa có thì đọc DB rồi lưu vào cache. Không cache kết quả không tìm thấy.
+// Bản đọc từ DB không được lưu nếu có ghi commit trong lúc đọc (generation của bảng hoặc rev của entry đã đổi),
+// chỉ còn khoảng hở nhỏ giữa bước so sánh và Set do Cache không hỗ trợ compare-and-set
+func (r *CachedRepository[T, ID]) FindByID(ctx context.Context, id ID) (*T, error) {
+	if !r.cacheable(ctx) {
+		return r.Repository.FindByID(ctx, id)
+	}
+	sch, err := r.schema()
+	if err != nil {
+		return nil, err
+	}
+
+	gen, err := r.generation(ctx)
+	if err != nil {
+		log.Printf("gormx cache get %s: %v", r.prefix, err)
+		return r.Repository.FindByID(ctx, id)
+	}
+	key := r.entryKey(ctx, gen, id)
+	if data, ok, err := r.cache.Get(ctx, key); err != nil {
+		log.Printf("gormx cache get %s: %v", key, err)
+	} else if ok {
+		entity := new(T)
+		if err := decodeEntity(ctx, sch, data, entity); err == nil {
+			return entity, nil
+		}
+		log.Printf("gormx cache decode %s: %v", key, err)
+	}
+	rev, _, revErr := r.cache.Get(ctx, key+":rev")
+
+	entity, err := r.Repository.FindByID(ctx, id)
+	if err != nil || revErr != nil || !r.unchanged(ctx, gen, key, rev) {
+		return entity, err
+	}
+	data, err := encodeEntity(ctx, sch, entity)
+	if err != nil {
+		log.Printf("gormx cache encode %s: %v", key, err)
+		return entity, nil
+	}
+	if err := r.cache.Set(ctx, key, data, r.ttl); err != nil {
+		log.Printf("gormx cache set %s: %v", key, err)
+	}
+	return entity, nil
+}
+
+// unchanged cho biết generation của bảng và rev của entry vẫn như trước khi đọc DB
+func (r *CachedRepository[T, ID]) unchanged(ctx context.Context, gen []byte, key string, rev []byte) bool {
+	curGen, ok, err := r.cache.Get(ctx, r.prefix+":gen")
+	if err != nil || !ok || !bytes.Equal(curGen, gen) {
+		return false
+	}
+	curRev, _, err := r.cache.Get(ctx, key+":rev")
+	return err == nil && bytes.Equal(curRev, rev)
+}
+
+func (r *CachedRepository[T, ID]) Update(ctx context.Context, entity *T) error {
+	if err := r.Repository.Update(ctx, entity); err != nil {
+		return err
+	}
+	id, ok := r.entityID(entity)
+	if !ok {
+		r.invalidateAll(ctx)
+		return nil
+	}
+	r.invalidate(ctx, id)
+	return nil
+}
+
+// Restore khôi phục entity rồi xóa cache, tránh giữ kết quả cũ của entity đã bị xóa
+func (r *CachedRepository[T, ID]) Restore(ctx context.Context, id ID) error {
+	if err := r.Repository.Restore(ctx, id); err != nil {
+		return err
+	}
+	r.invalidate(ctx, id)
+	return nil
+}
+
+func (r *CachedRepository[T, ID]) DeleteByID(ctx context.Context, id ID) error {
+	if err := r.Repository.DeleteByID(ctx, id); err != nil {
+		return err
+	}
+	r.invalidate(ctx, id)
+	return nil
+}
+
+func (r *CachedRepository[T, ID]) Upsert(ctx context.Context, items []T, conflictColumns []string, updateColumns []string) error {
+	if err := r.Repository.Upsert(ctx, items, conflictColumns, updateColumns); err != nil {
+		return err
+	}
+	r.invalidateAll(ctx)
+	return nil
+}
+
+func (r *CachedRepository[T, ID]) UpdateWhere(ctx context.Context, spec *Spec, values map[string]any) (int64, error) {
+	n, err := r.Repository.UpdateWhere(ctx, spec, values)
+	if err == nil && n > 0 {
+		r.invalidateAll(ctx)
+	}
+	return n, err
+}
+
+func (r *CachedRepository[T, ID]) DeleteWhere(ctx context.Context, spec *Spec) (int64, error) {
+	n, err := r.Repository.DeleteWhere(ctx, spec)
+	if err == nil && n > 0 {
+		r.invalidateAll(ctx)
+	}
+	return n, err
+}
+
+// cacheable: không dùng cache trong transaction (có thể thấy dữ liệu chưa commit), khi bỏ qua tenant
+// và khi ctx buộc đọc primary (UsePrimary, read-your-writes)
+func (r *CachedRepository[T, ID]) cacheable(ctx context.Context) bool {
+	return !InTransaction(ctx, r.DataSource) && !tenantBypassed(ctx) && !r.pinnedToPrimary(ctx)
+}
+
+// invalidate đổi rev rồi xóa entity khỏi cache sau khi transaction commit, FindByID đang đọc DB
+// thấy rev đổi sẽ không lưu bản cũ. Ghi khi bỏ qua tenant không biết key của tenant nào nên đổi generation của cả bảng
+func (r *CachedRepository[T, ID]) invalidate(ctx context.Context, id ID) {
+	if tenantBypassed(ctx) {
+		r.invalidateAll(ctx)
+		return
+	}
+	AfterCommit(ctx, func(ctx context.Context) {
+		gen, err := r.generation(ctx)
+		if err == nil {
+			key := r.entryKey(ctx, gen, id)
+			if err = r.cache.Set(ctx, key+":rev", newToken(), r.ttl); err == nil {
+				err = r.cache.Delete(ctx, key)
+			}
+		}
+		if err != nil {
+			log.Printf("gormx cache invalidate %s: %v", r.prefix, err)
+		}
+	})
+}
+
+// invalidateAll đổi generation sau khi transaction commit, mọi key cũ của bảng không còn được đọc
+func (r *CachedRepository[T, ID]) invalidateAll(ctx context.Context) {
+	AfterCommit(ctx, func(ctx context.Context) {
+		if _, err := r.newGeneration(ctx); err != nil {
+			log.Printf("gormx cache invalidate %s: %v", r.prefix, err)
+		}
+	})
+}
+
+func (r *CachedRepository[T, ID]) newGeneration(ctx context.Context) ([]byte, error) {
+	gen := newToken()
+	return gen, r.cache.Set(ctx, r.prefix+":gen", gen, 0)
+}
+
+// generation trả về generation hiện tại của bảng.
+// generation bị mất (hết bộ nhớ, flush...) thì tạo mới, không quay lại generation cũ có thể đã lỗi thời
+func (r *CachedRepository[T, ID]) generation(ctx context.Context) ([]byte, error) {
+	gen, ok, err := r.cache.Get(ctx, r.prefix+":gen")
+	if err != nil || ok {
+		return gen, err
+	}
+	return r.newGeneration(ctx)
+}
+
+// entryKey có dạng <prefix>:<fingerprint>[:v<version>]:g<generation>[:t<tenant>]:<id>
+func (r *CachedRepository[T, ID]) entryKey(ctx context.Context, gen []byte, id ID) string {
+	key := r.prefix + ":g" + string(gen)
+	if tenant, err := r.tenancy.resolve(ctx); err == nil {
+		key += ":t" + tenant
+	}
+	return key + ":" + fmt.Sprint(id)
+}
+
+// entityID lấy giá trị khóa chính của entity
+func (r *CachedRepository[T, ID]) entityID(entity *T) (ID, bool) {
+	var zero ID
+	sch, err := r.schema()
+	if err != nil || sch.PrioritizedPrimaryField == nil {
+		return zero, false
+	}
+	v, isZero := sch.PrioritizedPrimaryField.ValueOf(context.Background(), reflect.ValueOf(entity).Elem())
+	id, ok := v.(ID)
+	return id, ok && !isZero
+}
+
+func newToken() []byte {
+	return []byte(strconv.FormatInt(time.Now().UnixNano(), 36))
+}
+
+// schemaFingerprint băm danh sách cột và kiểu của entity, đổi cột sẽ đổi key cache
+func (r *Repository[T, ID]) schemaFingerprint() string {
+	h := fnv.New32a()
+	if sch, err := r.schema(); err == nil {
+		for _, f := range sch.Fields {
+			_, _ = fmt.Fprintf(h, "%s:%s:%s;", f.DBName, f.FieldType, f.DataType)
+		}
+	}
+	return strconv.FormatUint(uint64(h.Sum32()), 36)
+}
+
+func init() {
+	gob.Register(time.Time{})
+}
+
+// cachedEntity là dạng lưu của entity trong cache: giá trị theo tên cột như khi ghi vào DB,
+// nên giữ đủ mọi cột kể cả field có tag json:"-"
+type cachedEntity struct {
+	Columns map[string]any
+	// JSON chứa cột có serializer hoặc kiểu không đổi được sang driver.Value
+	JSON map[string][]byte
+}
+
+func encodeEntity(ctx context.Context, sch *schema.Schema, entity any) ([]byte, error) {
+	rv := reflect.Indirect(reflect.ValueOf(entity))
+	c := cachedEntity{Columns: make(map[string]any, len(sch.Fields))}
+	for _, f := range sch.Fields {
+		if f.DBName == "" {
+			continue
+		}
+		// ValueOf của cột có serializer trả về wrapper của GORM, lấy giá trị gốc của field
+		v := f.ReflectValueOf(ctx, rv).Interface()
+		if f.Serializer == nil {
+			v, _ = f.ValueOf(ctx, rv)
+			if dv, err := driver.DefaultParameterConverter.ConvertValue(v); err == nil {
+				c.Columns[f.DBName] = dv
+				continue
+			}
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", f.DBName, err)
+		}
+		if c.JSON == nil {
+			c.JSON = make(map[string][]byte)
+		}
+		c.JSON[f.DBName] = data
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&c); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeEntity(ctx context.Context, sch *schema.Schema, data []byte, entity any) error {
+	var c cachedEntity
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&c); err != nil {
+		return err
+	}
+	rv := reflect.Indirect(reflect.ValueOf(entity))
+	for _, f := range sch.Fields {
+		if f.DBName == "" {
+			continue
+		}
+		if raw, ok := c.JSON[f.DBName]; ok {
+			v := reflect.New(f.FieldType)
+			if err := json.Unmarshal(raw, v.Interface()); err != nil {
+				return fmt.Errorf("column %s: %w", f.DBName, err)
+			}
+			if err := f.Set(ctx, rv, v.Elem().Interface()); err != nil {
+				return fmt.Errorf("column %s: %w", f.DBName, err)
+			}
+			continue
+		}
+		v, ok := c.Columns[f.DBName]
+		if !ok {
+			return fmt.Errorf("column %s missing from cache entry", f.DBName)
+		}
+		if err := f.Set(ctx, rv, v); err != nil {
+			return fmt.Errorf("column %s: %w", f.DBName, err)
+		}
+	}
+	return nil
+}
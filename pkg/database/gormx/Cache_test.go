@@ -0,0 +1,159 @@
+package gormx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.io/xhkzeroone/goframex/pkg/database/gormx"
+	"github.io/xhkzeroone/goframex/pkg/database/gormx/gormxtest"
+	"gorm.io/gorm"
+)
+
+type account struct {
+	ID       uint              `gorm:"primaryKey"`
+	Email    string            `gorm:"size:64"`
+	Password string            `json:"-"`
+	Nickname *string           `gorm:"size:32"`
+	LastSeen time.Time         `json:"lastSeen"`
+	Labels   map[string]string `gorm:"serializer:json"`
+	gormx.SoftDeletable
+	gormx.Versioned
+}
+
+// countingCache đếm số lần đọc DB bằng cách ghi nhận mỗi lần Set entry
+type countingCache struct {
+	gormx.Cache
+	sets int
+}
+
+func (c *countingCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.sets++
+	return c.Cache.Set(ctx, key, value, ttl)
+}
+
+func newAccounts(t *testing.T) (*gormx.DataSource, *gormx.CachedRepository[account, uint], *countingCache) {
+	t.Helper()
+	ds := gormxtest.New(t, gormxtest.WithModels(&account{}))
+	cache := &countingCache{Cache: gormx.NewLRUCache(100)}
+	return ds, gormx.NewCachedRepository(gormx.NewRepository[account, uint](ds), cache), cache
+}
+
+func TestCachedRepositoryKeepsAllColumns(t *testing.T) {
+	ds, repo, _ := newAccounts(t)
+	ctx := context.Background()
+	nick := "ann"
+	seen := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	acc := &account{Email: "a@example.com", Password: "hash", Nickname: &nick, LastSeen: seen, Labels: map[string]string{"tier": "gold"}}
+	if err := repo.Insert(ctx, acc); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	if _, err := repo.FindByID(ctx, acc.ID); err != nil {
+		t.Fatalf("warm cache: %v", err)
+	}
+	// Ghi thẳng vào DB không xóa cache, lần đọc sau chắc chắn lấy từ cache
+	if err := ds.Exec("UPDATE accounts SET email = ?", "changed@example.com").Error; err != nil {
+		t.Fatalf("raw update: %v", err)
+	}
+	got, err := repo.FindByID(ctx, acc.ID)
+	if err != nil {
+		t.Fatalf("cached find: %v", err)
+	}
+	if got.Email != "a@example.com" || got.Password != "hash" || got.Nickname == nil || *got.Nickname != "ann" || !got.LastSeen.Equal(seen) ||
+		got.Labels["tier"] != "gold" || got.Version != 1 || got.DeletedAt.Valid {
+		t.Fatalf("cached entity = %+v", got)
+	}
+}
+
+func TestCachedRepositoryInvalidation(t *testing.T) {
+	_, repo, cache := newAccounts(t)
+	ctx := context.Background()
+	acc := &account{Email: "a@example.com"}
+	if err := repo.Insert(ctx, acc); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	_, _ = repo.FindByID(ctx, acc.ID)
+	sets := cache.sets
+	_, _ = repo.FindByID(ctx, acc.ID)
+	if cache.sets != sets {
+		t.Fatal("second FindByID did not hit the cache")
+	}
+
+	acc.Email = "b@example.com"
+	if err := repo.Update(ctx, acc); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if got, err := repo.FindByID(ctx, acc.ID); err != nil || got.Email != "b@example.com" {
+		t.Fatalf("after update = %+v, %v", got, err)
+	}
+
+	if err := repo.DeleteByID(ctx, acc.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := repo.FindByID(ctx, acc.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("after delete err = %v, want not found", err)
+	}
+	if err := repo.Restore(ctx, acc.ID); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if got, err := repo.FindByID(ctx, acc.ID); err != nil || got.Email != "b@example.com" {
+		t.Fatalf("after restore = %+v, %v", got, err)
+	}
+}
+
+func TestCachedRepositorySkipsStaleRead(t *testing.T) {
+	ds, repo, _ := newAccounts(t)
+	ctx := context.Background()
+	acc := &account{Email: "old@example.com"}
+	if err := repo.Insert(ctx, acc); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	// Ghi đồng thời commit ngay sau khi FindByID đọc xong bản cũ từ DB, trước khi kịp lưu vào cache
+	armed := true
+	err := ds.Callback().Query().After("gorm:query").Register("test:concurrent_write", func(db *gorm.DB) {
+		if !armed {
+			return
+		}
+		armed = false
+		changed := *acc
+		changed.Email = "new@example.com"
+		if err := repo.Update(context.Background(), &changed); err != nil {
+			t.Errorf("concurrent update: %v", err)
+		}
+	})
+	if err != nil {
+		t.Fatalf("register callback: %v", err)
+	}
+
+	if got, err := repo.FindByID(ctx, acc.ID); err != nil || got.Email != "old@example.com" {
+		t.Fatalf("racing find = %+v, %v", got, err)
+	}
+	if got, err := repo.FindByID(ctx, acc.ID); err != nil || got.Email != "new@example.com" {
+		t.Fatalf("find after race = %+v, %v; stale entity was cached", got, err)
+	}
+}
+
+func TestCachedRepositoryBypass(t *testing.T) {
+	ds, repo, cache := newAccounts(t)
+	acc := &account{Email: "a@example.com"}
+	if err := repo.Insert(context.Background(), acc); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	txCtx := gormxtest.Begin(t, ds)
+	for name, ctx := range map[string]context.Context{
+		"transaction": txCtx,
+		"primary":     gormx.UsePrimary(context.Background()),
+	} {
+		if _, err := repo.FindByID(ctx, acc.ID); err != nil {
+			t.Fatalf("%s find: %v", name, err)
+		}
+		if cache.sets != 0 {
+			t.Fatalf("%s read populated the cache", name)
+		}
+	}
+}
@@ -1,5 +1,7 @@
 package gormx
 
+import "time"
+
 type Config struct {
 	Host     string `mapstructure:"host" yaml:"host"`
 	Port     string `mapstructure:"port" yaml:"port"`
@@ -10,8 +12,55 @@ type Config struct {
 	SSLMode  string `mapstructure:"sslmode" yaml:"sslmode"`
 	Debug    bool   `mapstructure:"debug" yaml:"debug"`
 	Driver   string `mapstructure:"driver" yaml:"driver"`
+	// DSN thô, nếu có sẽ được dùng thay cho các field kết nối ở trên
+	DSN string `mapstructure:"dsn" yaml:"dsn"`
 
 	MaxOpenConns    int   `mapstructure:"max_open_conns" yaml:"max_open_conns"`
 	MaxIdleConns    int   `mapstructure:"max_idle_conns" yaml:"max_idle_conns"`
 	ConnMaxLifetime int64 `mapstructure:"conn_max_lifetime" yaml:"conn_max_lifetime"`
+
+	// SlowThreshold: truy vấn chậm hơn ngưỡng này sẽ được log cảnh báo, mặc định 200ms
+	SlowThreshold time.Duration `mapstructure:"slow_threshold" yaml:"slow_threshold"`
+	// LogQueryParams: ghi giá trị tham số vào log và span, mặc định ẩn (chỉ giữ placeholder)
+	LogQueryParams bool `mapstructure:"log_query_params" yaml:"log_query_params"`
+
+	// CursorSecret là khóa ký cursor phân trang, cần giống nhau giữa các instance. Repository.Cursor báo lỗi nếu thiếu khi debug tắt
+	CursorSecret string `mapstructure:"cursor_secret" yaml:"cursor_secret"`
+
+	// Replicas chỉ dùng cho truy vấn đọc, field bỏ trống sẽ lấy theo primary
+	Replicas []ReplicaConfig `mapstructure:"replicas" yaml:"replicas"`
+	// ReplicaPolicy: round_robin (mặc định), random, least_latency
+	ReplicaPolicy string `mapstructure:"replica_policy" yaml:"replica_policy"`
+	// ReadAfterWriteWindow: sau khi ghi, các lần đọc trong cùng context sẽ dùng primary trong khoảng thời gian này
+	ReadAfterWriteWindow time.Duration `mapstructure:"read_after_write_window" yaml:"read_after_write_window"`
+	// ReplicaHealthCheckInterval: chu kỳ ping replica, mặc định 10s
+	ReplicaHealthCheckInterval time.Duration `mapstructure:"replica_health_check_interval" yaml:"replica_health_check_interval"`
+}
+
+type ReplicaConfig struct {
+	Host     string `mapstructure:"host" yaml:"host"`
+	Port     string `mapstructure:"port" yaml:"port"`
+	User     string `mapstructure:"user" yaml:"user"`
+	Password string `mapstructure:"password" yaml:"password"`
+	DSN      string `mapstructure:"dsn" yaml:"dsn"`
+}
+
+// replicaConfig tạo Config cho replica dựa trên primary
+func (c *Config) replicaConfig(r ReplicaConfig) *Config {
+	out := *c
+	out.Replicas = nil
+	if r.Host != "" {
+		out.Host = r.Host
+	}
+	if r.Port != "" {
+		out.Port = r.Port
+	}
+	if r.User != "" {
+		out.User = r.User
+	}
+	if r.Password != "" {
+		out.Password = r.Password
+	}
+	out.DSN = r.DSN
+	return &out
 }
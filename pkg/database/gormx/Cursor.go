@@ -0,0 +1,364 @@
+package gormx
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// ErrInvalidCursor trả về khi cursor bị sửa, sai chữ ký hoặc không khớp thứ tự sắp xếp
+var ErrInvalidCursor = errors.New("gormx: invalid cursor")
+
+// CursorPage là kết quả phân trang keyset (cursor)
+type CursorPage[T any] struct {
+	Items      []T     `json:"items"`
+	NextCursor string  `json:"nextCursor,omitempty"`
+	PrevCursor string  `json:"prevCursor,omitempty"`
+	HasNext    bool    `json:"hasNext"`
+	HasPrev    bool    `json:"hasPrev"`
+	Limit      int     `json:"limit"`
+	Sort       []Order `json:"sort"`
+	// TotalCount chỉ có khi dùng WithExactCount hoặc WithEstimatedCount
+	TotalCount *int64 `json:"totalCount,omitempty"`
+}
+
+type cursorToken struct {
+	// Sort là chữ ký thứ tự sắp xếp, cursor chỉ hợp lệ với cùng thứ tự
+	Sort   string            `json:"s"`
+	Prev   bool              `json:"p,omitempty"`
+	Values []json.RawMessage `json:"v"`
+}
+
+type CursorOption func(o *cursorOptions)
+
+type cursorOptions struct {
+	exactCount     bool
+	estimatedCount bool
+}
+
+// WithExactCount trả kèm tổng số bản ghi bằng COUNT(*)
+func WithExactCount() CursorOption {
+	return func(o *cursorOptions) {
+		o.exactCount = true
+	}
+}
+
+// WithEstimatedCount trả kèm tổng số bản ghi ước lượng từ thống kê của Postgres (pg_class.reltuples)
+// khi không có điều kiện lọc, các trường hợp khác dùng COUNT(*)
+func WithEstimatedCount() CursorOption {
+	return func(o *cursorOptions) {
+		o.estimatedCount = true
+	}
+}
+
+var (
+	fallbackCursorKeyOnce sync.Once
+	fallbackCursorKey     []byte
+)
+
+// cursorKey trả về khóa ký cursor từ Config.CursorSecret. Khóa ngẫu nhiên theo process chỉ dùng ở chế độ debug,
+// ngoài debug cursor ký bằng khóa đó sẽ không dùng được giữa các instance hay sau khi deploy nên trả về ErrMissingCursorSecret
+func (p *DataSource) cursorKey() ([]byte, error) {
+	if p.Config != nil && p.Config.CursorSecret != "" {
+		return []byte(p.Config.CursorSecret), nil
+	}
+	if !p.debug {
+		return nil, ErrMissingCursorSecret
+	}
+	fallbackCursorKeyOnce.Do(func() {
+		fallbackCursorKey = make([]byte, 32)
+		_, _ = rand.Read(fallbackCursorKey)
+		log.Println("gormx cursor_secret is not configured, cursors are only valid for this process")
+	})
+	return fallbackCursorKey, nil
+}
+
+// Cursor phân trang keyset theo thứ tự của spec.OrderBy, khóa chính luôn được thêm làm cột phân định duy nhất.
+// after là cursor lấy từ NextCursor/PrevCursor của trang trước, rỗng để lấy trang đầu.
+// Các cột sắp xếp phải NOT NULL
+func (r *Repository[T, ID]) Cursor(ctx context.Context, spec *Spec, after string, limit int, opts ...CursorOption) (*CursorPage[T], error) {
+	opt := &cursorOptions{}
+	for _, o := range opts {
+		o(opt)
+	}
+	if limit <= 0 {
+		return nil, fmt.Errorf("gormx: cursor limit must be positive")
+	}
+	if spec != nil && len(spec.exprOrders) > 0 {
+		return nil, fmt.Errorf("gormx: cursor does not support OrderByExpr")
+	}
+
+	sch, err := r.schema()
+	if err != nil {
+		return nil, err
+	}
+	orders, fields, err := cursorOrders(sch, spec.Orders())
+	if err != nil {
+		return nil, err
+	}
+	sortKey := cursorSortKey(orders)
+	key, err := r.cursorKey()
+	if err != nil {
+		return nil, err
+	}
+
+	var token *cursorToken
+	if after != "" {
+		token, err = decodeCursor(after, key)
+		if err != nil {
+			return nil, err
+		}
+		if token.Sort != sortKey || len(token.Values) != len(fields) {
+			return nil, ErrInvalidCursor
+		}
+	}
+	backward := token != nil && token.Prev
+
+	// Chỉ lấy điều kiện lọc, sắp xếp do cursor quyết định
+	reader := r.Reader(ctx)
+	base := spec
+	if base != nil {
+		b := *spec
+		b.orders = nil
+		b.limit = 0
+		// Cột sắp xếp luôn phải được select để tạo cursor
+		if len(b.selects) > 0 {
+			b.selects = append([]string(nil), b.selects...)
+			for _, f := range fields {
+				b.selects = append(b.selects, f.DBName)
+			}
+			b.selects = uniqueStrings(b.selects)
+		}
+		base = &b
+	}
+	db, err := base.apply(reader.Model(new(T)), sch)
+	if err != nil {
+		return nil, err
+	}
+
+	if token != nil {
+		values, err := decodeCursorValues(token, fields)
+		if err != nil {
+			return nil, err
+		}
+		db = db.Clauses(clause.Where{Exprs: []clause.Expression{keysetCondition(orders, fields, values, backward)}})
+	}
+	for i, o := range orders {
+		desc := o.Desc != backward
+		db = db.Order(clause.OrderByColumn{Column: clause.Column{Table: clause.CurrentTable, Name: fields[i].DBName}, Desc: desc})
+	}
+
+	var items []T
+	if err := db.Limit(limit + 1).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	hasMore := len(items) > limit
+	if hasMore {
+		items = items[:limit]
+	}
+	if backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+
+	page := &CursorPage[T]{Items: items, Limit: limit, Sort: orders}
+	if backward {
+		page.HasPrev = hasMore
+		page.HasNext = true
+	} else {
+		page.HasNext = hasMore
+		page.HasPrev = token != nil
+	}
+	if len(items) > 0 {
+		if page.HasNext {
+			if page.NextCursor, err = encodeCursor(ctx, &items[len(items)-1], fields, sortKey, false, key); err != nil {
+				return nil, err
+			}
+		}
+		if page.HasPrev {
+			if page.PrevCursor, err = encodeCursor(ctx, &items[0], fields, sortKey, true, key); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if opt.exactCount || opt.estimatedCount {
+		total, err := r.cursorTotal(reader, base, sch, opt.estimatedCount)
+		if err != nil {
+			return nil, err
+		}
+		page.TotalCount = &total
+	}
+	return page, nil
+}
+
+func (r *Repository[T, ID]) cursorTotal(reader *gorm.DB, spec *Spec, sch *schema.Schema, estimate bool) (int64, error) {
+	// Ước lượng theo cả bảng nên không dùng cho entity TenantScoped
+	_, scoped := any(new(T)).(tenantScoped)
+	if estimate && !scoped && !spec.HasFilter() && r.Config != nil && r.Config.DriverName() == DriverPostgres {
+		var estimated float64
+		err := reader.Raw("SELECT reltuples FROM pg_class WHERE oid = to_regclass(?)", sch.Table).Scan(&estimated).Error
+		// reltuples = -1 khi bảng chưa được ANALYZE
+		if err == nil && estimated >= 0 {
+			return int64(estimated), nil
+		}
+	}
+	countDB, err := spec.applyFilter(reader.Model(new(T)), sch)
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	err = countDB.Count(&total).Error
+	return total, err
+}
+
+// cursorOrders chuẩn hóa thứ tự sắp xếp và thêm khóa chính làm tie-breaker
+func cursorOrders(sch *schema.Schema, orders []Order) ([]Order, []*schema.Field, error) {
+	out := make([]Order, 0, len(orders)+1)
+	fields := make([]*schema.Field, 0, len(orders)+1)
+	seen := make(map[string]bool)
+	for _, o := range orders {
+		f := sch.LookUpField(o.Column)
+		if f == nil || f.DBName == "" {
+			return nil, nil, fmt.Errorf("%w: %s of %s", ErrUnknownColumn, o.Column, sch.Name)
+		}
+		if seen[f.DBName] {
+			continue
+		}
+		seen[f.DBName] = true
+		out = append(out, Order{Column: f.DBName, Desc: o.Desc})
+		fields = append(fields, f)
+	}
+
+	pk := sch.PrioritizedPrimaryField
+	if pk == nil {
+		return nil, nil, fmt.Errorf("gormx: cursor pagination requires a primary key on %s", sch.Name)
+	}
+	if !seen[pk.DBName] {
+		desc := false
+		if len(out) > 0 {
+			desc = out[len(out)-1].Desc
+		}
+		out = append(out, Order{Column: pk.DBName, Desc: desc})
+		fields = append(fields, pk)
+	}
+	return out, fields, nil
+}
+
+func cursorSortKey(orders []Order) string {
+	parts := make([]string, len(orders))
+	for i, o := range orders {
+		dir := "asc"
+		if o.Desc {
+			dir = "desc"
+		}
+		parts[i] = o.Column + ":" + dir
+	}
+	return strings.Join(parts, ",")
+}
+
+// keysetCondition tạo (c1 > v1) OR (c1 = v1 AND c2 > v2) OR ... theo hướng của từng cột
+func keysetCondition(orders []Order, fields []*schema.Field, values []any, backward bool) clause.Expression {
+	ors := make([]clause.Expression, 0, len(orders))
+	for i := range orders {
+		ands := make([]clause.Expression, 0, i+1)
+		for j := 0; j < i; j++ {
+			ands = append(ands, clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: fields[j].DBName}, Value: values[j]})
+		}
+		col := clause.Column{Table: clause.CurrentTable, Name: fields[i].DBName}
+		if orders[i].Desc != backward {
+			ands = append(ands, clause.Lt{Column: col, Value: values[i]})
+		} else {
+			ands = append(ands, clause.Gt{Column: col, Value: values[i]})
+		}
+		ors = append(ors, clause.And(ands...))
+	}
+	return clause.Or(ors...)
+}
+
+func encodeCursor(ctx context.Context, item any, fields []*schema.Field, sortKey string, prev bool, key []byte) (string, error) {
+	rv := reflect.Indirect(reflect.ValueOf(item))
+	token := cursorToken{Sort: sortKey, Prev: prev, Values: make([]json.RawMessage, len(fields))}
+	for i, f := range fields {
+		v, _ := f.ValueOf(ctx, rv)
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("gormx: can not encode cursor value %s: %w", f.DBName, err)
+		}
+		token.Values[i] = raw
+	}
+
+	payload, err := json.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(payload) + "." + enc.EncodeToString(signCursor(payload, key)), nil
+}
+
+func decodeCursor(cursor string, key []byte) (*cursorToken, error) {
+	enc := base64.RawURLEncoding
+	payloadPart, sigPart, ok := strings.Cut(cursor, ".")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	payload, err := enc.DecodeString(payloadPart)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	sig, err := enc.DecodeString(sigPart)
+	if err != nil || !hmac.Equal(sig, signCursor(payload, key)) {
+		return nil, ErrInvalidCursor
+	}
+
+	var token cursorToken
+	if err := json.Unmarshal(payload, &token); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &token, nil
+}
+
+// decodeCursorValues giải mã giá trị về đúng kiểu Go của từng cột
+func decodeCursorValues(token *cursorToken, fields []*schema.Field) ([]any, error) {
+	values := make([]any, len(fields))
+	for i, f := range fields {
+		ptr := reflect.New(f.FieldType)
+		if err := json.Unmarshal(token.Values[i], ptr.Interface()); err != nil {
+			return nil, ErrInvalidCursor
+		}
+		values[i] = ptr.Elem().Interface()
+	}
+	return values, nil
+}
+
+func uniqueStrings(in []string) []string {
+	seen := make(map[string]bool, len(in))
+	out := in[:0]
+	for _, s := range in {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func signCursor(payload, key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,161 @@
+package gormx_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"testing"
+
+	"github.io/xhkzeroone/goframex/pkg/database/gormx"
+)
+
+func TestCursorPagination(t *testing.T) {
+	ctx, repo := seedProducts(t)
+	// price trùng nhau (carrot, eggplant) được phân định bằng khóa chính, cùng chiều với cột cuối
+	spec := gormx.NewSpec().OrderBy(gormx.Desc("price"))
+
+	first, err := repo.Cursor(ctx, spec, "", 2, gormx.WithExactCount())
+	if err != nil {
+		t.Fatalf("first page: %v", err)
+	}
+	if !slices.Equal(names(first.Items), []string{"durian", "apple"}) || !first.HasNext || first.HasPrev {
+		t.Fatalf("first page = %+v", first)
+	}
+	if first.TotalCount == nil || *first.TotalCount != 5 {
+		t.Fatalf("total = %v, want 5", first.TotalCount)
+	}
+
+	second, err := repo.Cursor(ctx, spec, first.NextCursor, 2)
+	if err != nil {
+		t.Fatalf("second page: %v", err)
+	}
+	if !slices.Equal(names(second.Items), []string{"eggplant", "carrot"}) || !second.HasNext || !second.HasPrev {
+		t.Fatalf("second page = %+v", second)
+	}
+
+	last, err := repo.Cursor(ctx, spec, second.NextCursor, 2)
+	if err != nil {
+		t.Fatalf("last page: %v", err)
+	}
+	if !slices.Equal(names(last.Items), []string{"banana"}) || last.HasNext || last.NextCursor != "" {
+		t.Fatalf("last page = %+v", last)
+	}
+
+	back, err := repo.Cursor(ctx, spec, last.PrevCursor, 2)
+	if err != nil {
+		t.Fatalf("previous page: %v", err)
+	}
+	if !slices.Equal(names(back.Items), names(second.Items)) || !back.HasPrev {
+		t.Fatalf("previous page = %+v", back)
+	}
+}
+
+func TestCursorWithFilter(t *testing.T) {
+	ctx, repo := seedProducts(t)
+	spec := gormx.NewSpec(gormx.Eq("category", "fruit")).OrderBy(gormx.Asc("name"))
+
+	var got []string
+	after := ""
+	for {
+		page, err := repo.Cursor(ctx, spec, after, 1)
+		if err != nil {
+			t.Fatalf("cursor: %v", err)
+		}
+		got = append(got, names(page.Items)...)
+		if !page.HasNext {
+			break
+		}
+		after = page.NextCursor
+	}
+	if want := []string{"apple", "banana", "durian"}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestCursorRejectsInvalidToken(t *testing.T) {
+	ctx, repo := seedProducts(t)
+	spec := gormx.NewSpec().OrderBy(gormx.Asc("price"))
+
+	page, err := repo.Cursor(ctx, spec, "", 2)
+	if err != nil {
+		t.Fatalf("cursor: %v", err)
+	}
+	tampered := []byte(page.NextCursor)
+	tampered[len(tampered)/2] ^= 1
+
+	tests := map[string]struct {
+		spec  *gormx.Spec
+		token string
+	}{
+		"tampered":   {spec, string(tampered)},
+		"garbage":    {spec, "not-a-cursor"},
+		"other sort": {gormx.NewSpec().OrderBy(gormx.Desc("price")), page.NextCursor},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := repo.Cursor(ctx, tt.spec, tt.token, 2); !errors.Is(err, gormx.ErrInvalidCursor) {
+				t.Fatalf("err = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
+
+var cursorDBSeq atomic.Int64
+
+// openProducts mở SQLite in-memory không qua gormxtest để tự chọn cursor_secret và debug
+func openProducts(t *testing.T, secret string, opts ...gormx.Option) *gormx.Repository[product, uint] {
+	t.Helper()
+	ds, err := gormx.Open(&gormx.Config{
+		Driver:       gormx.DriverSQLite,
+		DSN:          fmt.Sprintf("file:cursor_secret_%d?mode=memory&cache=shared", cursorDBSeq.Add(1)),
+		CursorSecret: secret,
+	}, opts...)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { _ = ds.Close() })
+	if err := ds.AutoMigrate(&product{}); err != nil {
+		t.Fatal(err)
+	}
+	repo := gormx.NewRepository[product, uint](ds)
+	items := []product{{ID: 1, Name: "a", Price: 1}, {ID: 2, Name: "b", Price: 2}, {ID: 3, Name: "c", Price: 3}}
+	if err := repo.InsertBatch(context.Background(), items, 0); err != nil {
+		t.Fatal(err)
+	}
+	return repo
+}
+
+func TestCursorSecret(t *testing.T) {
+	ctx := context.Background()
+	spec := gormx.NewSpec().OrderBy(gormx.Asc("price"))
+
+	// Thiếu cursor_secret ngoài debug không chặn Open, chỉ Cursor báo lỗi
+	repo := openProducts(t, "", gormx.WithDebug(false))
+	if n, err := repo.Count(ctx, nil); err != nil || n != 3 {
+		t.Fatalf("count = %d, %v", n, err)
+	}
+	if _, err := repo.Cursor(ctx, spec, "", 2); !errors.Is(err, gormx.ErrMissingCursorSecret) {
+		t.Fatalf("cursor err = %v, want ErrMissingCursorSecret", err)
+	}
+
+	debugRepo := openProducts(t, "", gormx.WithDebug(true))
+	if _, err := debugRepo.Cursor(ctx, spec, "", 2); err != nil {
+		t.Fatalf("cursor in debug mode: %v", err)
+	}
+
+	// Instance khác dùng cùng secret đọc được cursor, secret khác thì không
+	first := openProducts(t, "shared")
+	page, err := first.Cursor(ctx, spec, "", 2)
+	if err != nil {
+		t.Fatalf("cursor: %v", err)
+	}
+	next, err := openProducts(t, "shared", gormx.WithDebug(false)).Cursor(ctx, spec, page.NextCursor, 2)
+	if err != nil || !slices.Equal(names(next.Items), []string{"c"}) {
+		t.Fatalf("next page on other instance = %+v, %v", next, err)
+	}
+	if _, err := openProducts(t, "other").Cursor(ctx, spec, page.NextCursor, 2); !errors.Is(err, gormx.ErrInvalidCursor) {
+		t.Fatalf("cursor with other secret err = %v, want ErrInvalidCursor", err)
+	}
+}
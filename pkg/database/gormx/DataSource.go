@@ -1,23 +1,43 @@
 package gormx
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
-	"gorm.io/gorm"
 	"log"
 	"time"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
 )
 
+// ErrMissingCursorSecret trả về từ Cursor khi DataSource không ở chế độ debug mà chưa cấu hình cursor_secret
+var ErrMissingCursorSecret = errors.New("gormx: cursor_secret is required for cursor pagination when debug is disabled")
+
 type Option func(o *options)
 
 type options struct {
 	dialector  gorm.Dialector
 	gormConfig *gorm.Config
 	debug      *bool
+	tracer     trace.TracerProvider
+	meter      metric.MeterProvider
+	tenancy    tenancy
+
+	// Chỉ dùng bởi Manager
+	lazy             bool
+	reconnectInitial time.Duration
+	reconnectMax     time.Duration
 }
 
 type DataSource struct {
 	*gorm.DB
-	Config *Config
+	Config   *Config
+	replicas *replicaSet
+	tenancy  *tenancy
+	debug    bool
 }
 
 func WithDialector(d gorm.Dialector) Option {
@@ -38,6 +58,20 @@ func WithDebug(debug bool) Option {
 	}
 }
 
+// WithTracerProvider dùng TracerProvider riêng thay cho otel global
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(o *options) {
+		o.tracer = tp
+	}
+}
+
+// WithMeterProvider dùng MeterProvider riêng thay cho otel global
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(o *options) {
+		o.meter = mp
+	}
+}
+
 func Open(cfg *Config, opts ...Option) (*DataSource, error) {
 	if cfg == nil {
 		return nil, fmt.Errorf("config must not be nil")
@@ -47,55 +81,127 @@ func Open(cfg *Config, opts ...Option) (*DataSource, error) {
 		o(opt)
 	}
 
-	gormCfg := &gorm.Config{}
-	if opt.gormConfig != nil {
-		gormCfg = opt.gormConfig
+	dialector := opt.dialector
+	if dialector == nil {
+		d, err := NewDialector(cfg)
+		if err != nil {
+			return nil, err
+		}
+		dialector = d
 	}
 
-	db, err := gorm.Open(opt.dialector, gormCfg)
-	if err != nil {
-		log.Printf("failed to connect database: %v", err)
-		return nil, err
-	}
-
-	sqlDB, err := db.DB()
-	if err != nil {
-		return nil, err
+	// gorm.Open ghi đè lên *gorm.Config, nên giữ bản gốc để tạo bản sao cho từng replica
+	baseCfg := gorm.Config{}
+	if opt.gormConfig != nil {
+		baseCfg = *opt.gormConfig
 	}
-	if err := sqlDB.Ping(); err != nil {
-		return nil, err
+	// Postgres dùng search_path trong DSN, các driver khác dùng tiền tố schema cho tên bảng
+	if cfg.Schema != "" && baseCfg.NamingStrategy == nil {
+		switch cfg.DriverName() {
+		case DriverMySQL, DriverSQLServer:
+			baseCfg.NamingStrategy = schema.NamingStrategy{TablePrefix: cfg.Schema + "."}
+		}
 	}
 
-	if cfg.MaxOpenConns > 0 {
-		sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	// Log truy vấn qua logrusx, span và metric qua OpenTelemetry
+	if baseCfg.Logger == nil {
+		baseCfg.Logger = NewQueryLogger(cfg)
 	}
-	if cfg.MaxIdleConns > 0 {
-		sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	obs, err := newObservability(cfg, opt.tracer, opt.meter)
+	if err != nil {
+		return nil, err
 	}
-	if cfg.ConnMaxLifetime > 0 {
-		sqlDB.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Second)
+	plugins := make(map[string]gorm.Plugin, len(baseCfg.Plugins)+1)
+	for name, p := range baseCfg.Plugins {
+		plugins[name] = p
 	}
+	plugins[obs.Name()] = obs
+	baseCfg.Plugins = plugins
 
 	debugMode := cfg.Debug
 	if opt.debug != nil {
 		debugMode = *opt.debug
 	}
+	primaryCfg := baseCfg
+	db, err := openDB(dialector, &primaryCfg, cfg)
+	if err != nil {
+		log.Printf("failed to connect database: %v", err)
+		return nil, err
+	}
+	tenancy := opt.tenancy
+	if err := errors.Join(registerMixinCallbacks(db), registerTenantCallbacks(db, &tenancy)); err != nil {
+		if sqlDB, dbErr := db.DB(); dbErr == nil {
+			_ = sqlDB.Close()
+		}
+		return nil, err
+	}
 	if debugMode {
 		db = db.Debug()
 		log.Println("GORM debug mode is enabled")
 	}
 
+	ds := &DataSource{DB: db, Config: cfg, tenancy: &tenancy, debug: debugMode}
+	if len(cfg.Replicas) > 0 {
+		replicas, err := openReplicas(cfg, baseCfg, debugMode, ds.tenancy)
+		if err != nil {
+			_ = ds.Close()
+			return nil, err
+		}
+		ds.replicas = replicas
+	}
+
 	log.Println("Successfully connected to database")
-	return &DataSource{DB: db, Config: cfg}, nil
+	return ds, nil
+}
+
+// openDB mở kết nối, ping và áp dụng cấu hình pool
+func openDB(dialector gorm.Dialector, gormCfg *gorm.Config, cfg *Config) (*gorm.DB, error) {
+	db, err := gorm.Open(dialector, gormCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	if !gormCfg.DisableAutomaticPing {
+		if err := sqlDB.Ping(); err != nil {
+			_ = sqlDB.Close()
+			return nil, err
+		}
+	}
+
+	applyPool(sqlDB, cfg.poolConfig())
+	return db, nil
 }
 
+// applyPool áp dụng các field khác nil của pool, có thể gọi lại khi đang chạy
+func applyPool(sqlDB *sql.DB, pool PoolConfig) {
+	if pool.MaxOpenConns != nil {
+		sqlDB.SetMaxOpenConns(*pool.MaxOpenConns)
+	}
+	if pool.MaxIdleConns != nil {
+		sqlDB.SetMaxIdleConns(*pool.MaxIdleConns)
+	}
+	if pool.ConnMaxLifetime != nil {
+		sqlDB.SetConnMaxLifetime(*pool.ConnMaxLifetime)
+	}
+}
+
+// Close đóng kết nối primary và toàn bộ replica
 func (p *DataSource) Close() error {
 	if p == nil || p.DB == nil {
 		return nil
 	}
+	var errs []error
+	if p.replicas != nil {
+		errs = append(errs, p.replicas.close())
+	}
 	sqlDB, err := p.DB.DB()
 	if err != nil {
-		return err
+		return errors.Join(append(errs, err)...)
 	}
-	return sqlDB.Close()
+	errs = append(errs, sqlDB.Close())
+	return errors.Join(errs...)
 }
@@ -0,0 +1,130 @@
+package gormx
+
+import (
+	"database/sql"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlserver"
+	"gorm.io/gorm"
+)
+
+const (
+	DriverPostgres  = "postgres"
+	DriverMySQL     = "mysql"
+	DriverSQLite    = "sqlite"
+	DriverSQLServer = "sqlserver"
+)
+
+// DriverName chuẩn hóa tên driver trong config, mặc định là postgres
+func (c *Config) DriverName() string {
+	switch d := strings.ToLower(strings.TrimSpace(c.Driver)); d {
+	case "", "postgresql", "pg":
+		return DriverPostgres
+	case "sqlite3":
+		return DriverSQLite
+	case "mssql":
+		return DriverSQLServer
+	default:
+		return d
+	}
+}
+
+// BuildDSN tạo DSN từ config theo driver, ưu tiên DSN thô nếu có
+func (c *Config) BuildDSN() (string, error) {
+	if c.DSN != "" {
+		return c.DSN, nil
+	}
+
+	switch c.DriverName() {
+	case DriverPostgres:
+		var parts []string
+		for _, kv := range [][2]string{
+			{"host", c.Host}, {"port", c.Port}, {"user", c.User}, {"password", c.Password},
+			{"dbname", c.DBName}, {"sslmode", c.SSLMode}, {"search_path", c.Schema},
+		} {
+			if kv[1] != "" {
+				parts = append(parts, kv[0]+"="+quotePostgresValue(kv[1]))
+			}
+		}
+		return strings.Join(parts, " "), nil
+	case DriverMySQL:
+		// FormatDSN escape password và tên database, riêng user chứa ":" không viết được thành DSN
+		// (NewDialector vẫn kết nối được vì không đi qua chuỗi DSN)
+		if strings.Contains(c.User, ":") {
+			return "", fmt.Errorf("mysql user %q contains ':' and cannot be written as a DSN", c.User)
+		}
+		return c.mysqlConfig().FormatDSN(), nil
+	case DriverSQLite:
+		if c.DBName == "" {
+			return "file::memory:?cache=shared", nil
+		}
+		return c.DBName, nil
+	case DriverSQLServer:
+		u := &url.URL{
+			Scheme:   "sqlserver",
+			User:     url.UserPassword(c.User, c.Password),
+			Host:     c.Host + ":" + c.Port,
+			RawQuery: url.Values{"database": {c.DBName}}.Encode(),
+		}
+		return u.String(), nil
+	default:
+		return "", fmt.Errorf("unsupported database driver: %q (supported: postgres, mysql, sqlite, sqlserver)", c.Driver)
+	}
+}
+
+func (c *Config) mysqlConfig() *mysqldriver.Config {
+	mc := mysqldriver.NewConfig()
+	mc.User, mc.Passwd = c.User, c.Password
+	mc.Net, mc.Addr = "tcp", net.JoinHostPort(c.Host, c.Port)
+	mc.DBName = c.DBName
+	mc.ParseTime, mc.Loc = true, time.Local
+	mc.Params = map[string]string{"charset": "utf8mb4"}
+	return mc
+}
+
+// quotePostgresValue đặt giá trị trong dấu nháy đơn khi chứa khoảng trắng hoặc ký tự đặc biệt
+func quotePostgresValue(v string) string {
+	if !strings.ContainsAny(v, " '\\") {
+		return v
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v) + "'"
+}
+
+// NewDialector tạo gorm.Dialector tương ứng với driver trong config
+func NewDialector(cfg *Config) (gorm.Dialector, error) {
+	if cfg.DriverName() == DriverMySQL && cfg.DSN == "" {
+		// Kết nối bằng connector từ mysql.Config, không qua chuỗi DSN nên mọi ký tự trong user/password đều dùng được
+		mc := cfg.mysqlConfig()
+		connector, err := mysqldriver.NewConnector(mc)
+		if err != nil {
+			return nil, err
+		}
+		return mysql.New(mysql.Config{Conn: sql.OpenDB(connector), DSNConfig: mc}), nil
+	}
+
+	dsn, err := cfg.BuildDSN()
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.DriverName() {
+	case DriverPostgres:
+		return postgres.Open(dsn), nil
+	case DriverMySQL:
+		return mysql.Open(dsn), nil
+	case DriverSQLite:
+		return sqlite.Open(dsn), nil
+	case DriverSQLServer:
+		return sqlserver.Open(dsn), nil
+	default:
+		return nil, fmt.Errorf("unsupported database driver: %q (supported: postgres, mysql, sqlite, sqlserver)", cfg.Driver)
+	}
+}
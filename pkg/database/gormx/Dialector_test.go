@@ -0,0 +1,122 @@
+package gormx_test
+
+import (
+	"strings"
+	"testing"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/microsoft/go-mssqldb/msdsn"
+	"github.io/xhkzeroone/goframex/pkg/database/gormx"
+	"gorm.io/driver/mysql"
+)
+
+// Giá trị chứa ký tự đặc biệt phải được driver đọc lại nguyên vẹn
+var dsnCredentials = []struct{ name, user, password, db string }{
+	{"plain", "app", "secret", "appdb"},
+	{"at sign", "app@corp", "p@ss@word", "app@db"},
+	{"colon and slash", "app:ro", "p:a/s/s", "app/db"},
+	{"query chars", "app?x", "p?a=s&s#1", "app?db"},
+	{"percent", "app%20", "100%pass%2F", "db%41"},
+	{"space and quote", "app user", `it's a \ pass`, "my db"},
+}
+
+func TestBuildDSNPostgres(t *testing.T) {
+	for _, tc := range dsnCredentials {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &gormx.Config{Host: "db.local", Port: "5432", User: tc.user, Password: tc.password, DBName: tc.db, SSLMode: "disable", Schema: "app"}
+			dsn, err := cfg.BuildDSN()
+			if err != nil {
+				t.Fatalf("build: %v", err)
+			}
+			parsed, err := pgconn.ParseConfig(dsn)
+			if err != nil {
+				t.Fatalf("parse %q: %v", dsn, err)
+			}
+			if parsed.User != tc.user || parsed.Password != tc.password || parsed.Database != tc.db {
+				t.Errorf("%q parsed as user=%q password=%q db=%q", dsn, parsed.User, parsed.Password, parsed.Database)
+			}
+			if parsed.Host != "db.local" || parsed.Port != 5432 || parsed.RuntimeParams["search_path"] != "app" {
+				t.Errorf("%q parsed as host=%q port=%d params=%v", dsn, parsed.Host, parsed.Port, parsed.RuntimeParams)
+			}
+		})
+	}
+}
+
+func TestBuildDSNMySQL(t *testing.T) {
+	for _, tc := range dsnCredentials {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &gormx.Config{Driver: "mysql", Host: "db.local", Port: "3306", User: tc.user, Password: tc.password, DBName: tc.db}
+			// NewDialector dùng connector nên user chứa ":" (không viết được thành DSN) vẫn kết nối được
+			dialector, err := gormx.NewDialector(cfg)
+			if err != nil {
+				t.Fatalf("dialector: %v", err)
+			}
+			if mc := dialector.(*mysql.Dialector).DSNConfig; mc.User != tc.user || mc.Passwd != tc.password || mc.DBName != tc.db {
+				t.Errorf("dialector config user=%q password=%q db=%q", mc.User, mc.Passwd, mc.DBName)
+			}
+
+			dsn, err := cfg.BuildDSN()
+			if strings.Contains(tc.user, ":") {
+				if err == nil {
+					t.Errorf("BuildDSN with user %q = %q, want error", tc.user, dsn)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("build: %v", err)
+			}
+			parsed, err := mysqldriver.ParseDSN(dsn)
+			if err != nil {
+				t.Fatalf("parse %q: %v", dsn, err)
+			}
+			if parsed.User != tc.user || parsed.Passwd != tc.password || parsed.DBName != tc.db {
+				t.Errorf("%q parsed as user=%q password=%q db=%q", dsn, parsed.User, parsed.Passwd, parsed.DBName)
+			}
+			if parsed.Addr != "db.local:3306" || !parsed.ParseTime {
+				t.Errorf("%q parsed as addr=%q parseTime=%v", dsn, parsed.Addr, parsed.ParseTime)
+			}
+		})
+	}
+}
+
+func TestBuildDSNSQLServer(t *testing.T) {
+	for _, tc := range dsnCredentials {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &gormx.Config{Driver: "mssql", Host: "db.local", Port: "1433", User: tc.user, Password: tc.password, DBName: tc.db}
+			dsn, err := cfg.BuildDSN()
+			if err != nil {
+				t.Fatalf("build: %v", err)
+			}
+			parsed, err := msdsn.Parse(dsn)
+			if err != nil {
+				t.Fatalf("parse %q: %v", dsn, err)
+			}
+			if parsed.User != tc.user || parsed.Password != tc.password || parsed.Database != tc.db {
+				t.Errorf("%q parsed as user=%q password=%q db=%q", dsn, parsed.User, parsed.Password, parsed.Database)
+			}
+			if parsed.Host != "db.local" || parsed.Port != 1433 {
+				t.Errorf("%q parsed as host=%q port=%d", dsn, parsed.Host, parsed.Port)
+			}
+		})
+	}
+}
+
+func TestBuildDSNOther(t *testing.T) {
+	for _, tc := range []struct {
+		cfg  gormx.Config
+		want string
+	}{
+		{gormx.Config{Driver: "sqlite", DBName: "data/app?.db"}, "data/app?.db"},
+		{gormx.Config{Driver: "sqlite3"}, "file::memory:?cache=shared"},
+		{gormx.Config{Driver: "mysql", DSN: "raw:dsn@/db"}, "raw:dsn@/db"},
+	} {
+		if got, err := tc.cfg.BuildDSN(); err != nil || got != tc.want {
+			t.Errorf("BuildDSN(%+v) = %q, %v, want %q", tc.cfg, got, err, tc.want)
+		}
+	}
+	cfg := gormx.Config{Driver: "oracle"}
+	if _, err := cfg.BuildDSN(); err == nil || !strings.Contains(err.Error(), "unsupported") {
+		t.Errorf("unsupported driver err = %v", err)
+	}
+}
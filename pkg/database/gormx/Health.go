@@ -0,0 +1,105 @@
+package gormx
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const (
+	HealthUp           = "up"
+	HealthDown         = "down"
+	HealthNotConnected = "not_connected"
+)
+
+// HealthStatus là trạng thái kết nối của một DataSource
+type HealthStatus struct {
+	Name     string          `json:"name"`
+	Status   string          `json:"status"`
+	Latency  time.Duration   `json:"latency"`
+	Error    string          `json:"error,omitempty"`
+	Stats    sql.DBStats     `json:"stats"`
+	Replicas []ReplicaStatus `json:"replicas,omitempty"`
+}
+
+// Health ping primary và trả về độ trễ, thống kê pool (open, in-use, wait count...) cùng trạng thái replica
+func (p *DataSource) Health(ctx context.Context) HealthStatus {
+	status := HealthStatus{Status: HealthUp, Replicas: p.ReplicaStatus()}
+	sqlDB, err := p.DB.DB()
+	if err != nil {
+		status.Status, status.Error = HealthDown, err.Error()
+		return status
+	}
+
+	start := time.Now()
+	err = sqlDB.PingContext(ctx)
+	status.Latency = time.Since(start)
+	status.Stats = sqlDB.Stats()
+	if err != nil {
+		status.Status, status.Error = HealthDown, err.Error()
+	}
+	return status
+}
+
+// PoolConfig là cấu hình pool dùng khi reload. Field nil giữ nguyên giá trị hiện tại, field khác nil
+// được áp dụng kể cả khi bằng 0: MaxOpenConns, ConnMaxLifetime = 0 là không giới hạn,
+// MaxIdleConns = 0 là không giữ kết nối rảnh (mặc định của database/sql là 2)
+type PoolConfig struct {
+	MaxOpenConns    *int
+	MaxIdleConns    *int
+	ConnMaxLifetime *time.Duration
+}
+
+// poolConfig lấy cấu hình pool từ Config, giá trị 0 trong file config nghĩa là dùng mặc định của database/sql
+func (c *Config) poolConfig() PoolConfig {
+	var pool PoolConfig
+	if c.MaxOpenConns > 0 {
+		pool.MaxOpenConns = &c.MaxOpenConns
+	}
+	if c.MaxIdleConns > 0 {
+		pool.MaxIdleConns = &c.MaxIdleConns
+	}
+	if c.ConnMaxLifetime > 0 {
+		lifetime := time.Duration(c.ConnMaxLifetime) * time.Second
+		pool.ConnMaxLifetime = &lifetime
+	}
+	return pool
+}
+
+// merge trả về p với các field khác nil của o ghi đè lên
+func (p PoolConfig) merge(o PoolConfig) PoolConfig {
+	if o.MaxOpenConns != nil {
+		p.MaxOpenConns = o.MaxOpenConns
+	}
+	if o.MaxIdleConns != nil {
+		p.MaxIdleConns = o.MaxIdleConns
+	}
+	if o.ConnMaxLifetime != nil {
+		p.ConnMaxLifetime = o.ConnMaxLifetime
+	}
+	return p
+}
+
+// ReloadPool áp dụng lại cấu hình pool cho primary và replica mà không cần mở lại kết nối
+func (p *DataSource) ReloadPool(pool PoolConfig) error {
+	sqlDB, err := p.DB.DB()
+	if err != nil {
+		return err
+	}
+	applyPool(sqlDB, pool)
+
+	if p.replicas != nil {
+		merged := p.replicas.pool.Load().merge(pool)
+		p.replicas.pool.Store(&merged)
+		for _, r := range p.replicas.replicas {
+			db := r.db.Load()
+			if db == nil {
+				continue
+			}
+			if replicaDB, err := db.DB(); err == nil {
+				applyPool(replicaDB, pool)
+			}
+		}
+	}
+	return nil
+}
@@ -1,57 +1,311 @@
 package gormx
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"sync"
+	"time"
 )
 
+// ErrNotConnected trả về khi DataSource chưa kết nối được (lazy lỗi hoặc đang reconnect)
+var ErrNotConnected = errors.New("gormx: datasource is not connected")
+
+// WithLazyConnect chỉ kết nối khi Manager.Get được gọi lần đầu
+func WithLazyConnect() Option {
+	return func(o *options) {
+		o.lazy = true
+	}
+}
+
+// WithReconnectBackoff: kết nối thất bại lúc khởi động sẽ được thử lại trong nền,
+// thời gian chờ tăng gấp đôi từ initial tới maxDelay. Register không trả lỗi trong trường hợp này
+func WithReconnectBackoff(initial, maxDelay time.Duration) Option {
+	return func(o *options) {
+		o.reconnectInitial = initial
+		o.reconnectMax = maxDelay
+	}
+}
+
 type Manager struct {
 	mu        sync.RWMutex
-	instances map[string]*DataSource
+	instances map[string]*managedDataSource
+}
+
+// managedDataSource giữ cấu hình và trạng thái kết nối của một DataSource
+type managedDataSource struct {
+	name string
+	cfg  *Config
+	opts []Option
+	opt  options
+	// pool là cấu hình pool từ Reload, áp dụng sau mỗi lần kết nối
+	pool PoolConfig
+
+	mu          sync.Mutex
+	ds          *DataSource
+	lastErr     error
+	attempts    int
+	nextAttempt time.Time
+	reconnect   bool
+
+	stop chan struct{}
+	done chan struct{}
 }
 
 // NewManager creates a new Manager
 func NewManager() *Manager {
 	return &Manager{
-		instances: make(map[string]*DataSource),
+		instances: make(map[string]*managedDataSource),
 	}
 }
 
-// Register a new database instance with optional overrides
+// Register a new database instance with optional overrides.
+// Mặc định kết nối ngay, dùng WithLazyConnect để hoãn tới lần Get đầu tiên
 func (m *Manager) Register(name string, cfg *Config, opts ...Option) error {
-	db, err := Open(cfg, opts...)
-	if err != nil {
-		return err
+	if cfg == nil {
+		return fmt.Errorf("config must not be nil")
+	}
+	if m.exists(name) {
+		return fmt.Errorf("database instance already registered: %s", name)
+	}
+
+	e := &managedDataSource{name: name, cfg: cfg, opts: opts, stop: make(chan struct{}), done: make(chan struct{})}
+	for _, o := range opts {
+		o(&e.opt)
+	}
+
+	if e.opt.lazy {
+		close(e.done)
+	} else if _, err := e.connect(); err != nil {
+		if e.opt.reconnectInitial <= 0 {
+			return err
+		}
+		log.Printf("gormx [%s] connect failed, retrying in background: %v", name, err)
+		e.reconnect = true
+		go e.reconnectLoop()
+	} else {
+		close(e.done)
 	}
 
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.instances[name] = db
+	if _, ok := m.instances[name]; ok {
+		_ = e.close()
+		return fmt.Errorf("database instance already registered: %s", name)
+	}
+	m.instances[name] = e
 	return nil
 }
 
-// Get retrieves a registered database by name
+// Get retrieves a registered database by name, kết nối nếu là lazy
 func (m *Manager) Get(name string) (*DataSource, error) {
 	m.mu.RLock()
-	defer m.mu.RUnlock()
-
-	db, ok := m.instances[name]
+	e, ok := m.instances[name]
+	m.mu.RUnlock()
 	if !ok {
 		return nil, fmt.Errorf("database instance not found: %s", name)
 	}
-	return db, nil
+	return e.get()
 }
 
-// CloseAll closes all database connections
+// Unregister gỡ và đóng kết nối của database instance
+func (m *Manager) Unregister(name string) error {
+	m.mu.Lock()
+	e, ok := m.instances[name]
+	delete(m.instances, name)
+	m.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("database instance not found: %s", name)
+	}
+	return e.close()
+}
+
+// Reload áp dụng lại cấu hình pool của instance, field nil của pool giữ nguyên giá trị hiện tại.
+// Cấu hình được giữ lại và áp dụng cả khi instance kết nối lại
+func (m *Manager) Reload(name string, pool PoolConfig) error {
+	m.mu.RLock()
+	e, ok := m.instances[name]
+	m.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("database instance not found: %s", name)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.pool = e.pool.merge(pool)
+	if e.ds == nil {
+		return nil
+	}
+	return e.ds.ReloadPool(pool)
+}
+
+// Health trả về trạng thái của từng instance, các instance được ping song song
+func (m *Manager) Health(ctx context.Context) map[string]HealthStatus {
+	m.mu.RLock()
+	entries := make([]*managedDataSource, 0, len(m.instances))
+	for _, e := range m.instances {
+		entries = append(entries, e)
+	}
+	m.mu.RUnlock()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	out := make(map[string]HealthStatus, len(entries))
+	for _, e := range entries {
+		wg.Add(1)
+		go func(e *managedDataSource) {
+			defer wg.Done()
+			status := e.health(ctx)
+			mu.Lock()
+			out[e.name] = status
+			mu.Unlock()
+		}(e)
+	}
+	wg.Wait()
+	return out
+}
+
+// CloseAll closes all database connections, trả về lỗi gộp của các instance
 func (m *Manager) CloseAll() error {
 	m.mu.Lock()
-	defer m.mu.Unlock()
+	entries := m.instances
+	m.instances = make(map[string]*managedDataSource)
+	m.mu.Unlock()
 
-	for name, db := range m.instances {
-		if err := db.Close(); err != nil {
-			log.Printf("failed to close gormx [%s]: %v", name, err)
+	var errs []error
+	for name, e := range entries {
+		if err := e.close(); err != nil {
+			errs = append(errs, fmt.Errorf("gormx [%s]: %w", name, err))
 		}
 	}
-	return nil
+	return errors.Join(errs...)
+}
+
+func (m *Manager) exists(name string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.instances[name]
+	return ok
+}
+
+func (e *managedDataSource) get() (*DataSource, error) {
+	e.mu.Lock()
+	ds, reconnecting, lastErr := e.ds, e.reconnect, e.lastErr
+	e.mu.Unlock()
+	if ds != nil {
+		return ds, nil
+	}
+	if reconnecting {
+		return nil, fmt.Errorf("%w: %s: %v", ErrNotConnected, e.name, lastErr)
+	}
+	return e.connect()
+}
+
+// connect mở kết nối nếu chưa có, tôn trọng thời gian chờ backoff sau lần lỗi trước
+func (e *managedDataSource) connect() (*DataSource, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.ds != nil {
+		return e.ds, nil
+	}
+	if !e.nextAttempt.IsZero() && time.Now().Before(e.nextAttempt) {
+		return nil, fmt.Errorf("%w: %s: %v", ErrNotConnected, e.name, e.lastErr)
+	}
+
+	ds, err := Open(e.cfg, e.opts...)
+	if err == nil {
+		// Cấu hình pool từ Reload ghi đè cấu hình ban đầu
+		if err = ds.ReloadPool(e.pool); err != nil {
+			_ = ds.Close()
+		}
+	}
+	if err != nil {
+		e.lastErr = err
+		e.attempts++
+		if delay := e.backoff(); delay > 0 {
+			e.nextAttempt = time.Now().Add(delay)
+		}
+		return nil, err
+	}
+	e.ds, e.lastErr, e.attempts, e.nextAttempt = ds, nil, 0, time.Time{}
+	return ds, nil
+}
+
+// backoff trả về thời gian chờ trước lần thử tiếp theo: initial * 2^(attempts-1), tối đa maxDelay
+func (e *managedDataSource) backoff() time.Duration {
+	initial, maxDelay := e.opt.reconnectInitial, e.opt.reconnectMax
+	if initial <= 0 {
+		return 0
+	}
+	delay := initial
+	for i := 1; i < e.attempts; i++ {
+		delay *= 2
+		if maxDelay > 0 && delay >= maxDelay {
+			return maxDelay
+		}
+	}
+	return delay
+}
+
+func (e *managedDataSource) reconnectLoop() {
+	defer close(e.done)
+	for {
+		e.mu.Lock()
+		wait := time.Until(e.nextAttempt)
+		e.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-e.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if _, err := e.connect(); err != nil {
+			log.Printf("gormx [%s] reconnect attempt failed: %v", e.name, err)
+			continue
+		}
+		e.mu.Lock()
+		e.reconnect = false
+		e.mu.Unlock()
+		log.Printf("gormx [%s] connected after retry", e.name)
+		return
+	}
+}
+
+func (e *managedDataSource) health(ctx context.Context) HealthStatus {
+	e.mu.Lock()
+	ds, lastErr := e.ds, e.lastErr
+	e.mu.Unlock()
+
+	if ds == nil {
+		status := HealthStatus{Name: e.name, Status: HealthNotConnected}
+		if lastErr != nil {
+			status.Error = lastErr.Error()
+		}
+		return status
+	}
+	status := ds.Health(ctx)
+	status.Name = e.name
+	return status
+}
+
+func (e *managedDataSource) close() error {
+	select {
+	case <-e.stop:
+	default:
+		close(e.stop)
+	}
+	<-e.done
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.ds == nil {
+		return nil
+	}
+	err := e.ds.Close()
+	e.ds = nil
+	return err
 }
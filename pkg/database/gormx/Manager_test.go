@@ -0,0 +1,70 @@
+package gormx_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.io/xhkzeroone/goframex/pkg/database/gormx"
+)
+
+func TestManagerHealth(t *testing.T) {
+	dir := t.TempDir()
+	m := gormx.NewManager()
+	defer m.CloseAll()
+	sqlite := func(name string) *gormx.Config {
+		return &gormx.Config{Driver: gormx.DriverSQLite, DSN: filepath.Join(dir, name+".db"), CursorSecret: "test"}
+	}
+
+	for _, name := range []string{"main", "closed"} {
+		if err := m.Register(name, sqlite(name)); err != nil {
+			t.Fatalf("register %s: %v", name, err)
+		}
+	}
+	if err := m.Register("lazy", sqlite("lazy"), gormx.WithLazyConnect()); err != nil {
+		t.Fatalf("register lazy: %v", err)
+	}
+	broken := &gormx.Config{Driver: gormx.DriverSQLite, DSN: filepath.Join(dir, "missing", "broken.db")}
+	if err := m.Register("broken", broken, gormx.WithReconnectBackoff(time.Hour, time.Hour)); err != nil {
+		t.Fatalf("register with reconnect: %v", err)
+	}
+
+	// DataSource bị đóng ngoài Manager được báo down thay vì up
+	closed, err := m.Get("closed")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if err := closed.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	health := m.Health(context.Background())
+	if len(health) != 4 {
+		t.Fatalf("health has %d entries, want 4: %+v", len(health), health)
+	}
+	for name, want := range map[string]string{
+		"main":   gormx.HealthUp,
+		"closed": gormx.HealthDown,
+		"lazy":   gormx.HealthNotConnected,
+		"broken": gormx.HealthNotConnected,
+	} {
+		if got := health[name]; got.Name != name || got.Status != want {
+			t.Errorf("%s: %+v, want status %s", name, got, want)
+		}
+	}
+	if health["main"].Error != "" || health["closed"].Error == "" || health["broken"].Error == "" || health["lazy"].Error != "" {
+		t.Errorf("unexpected errors: %+v", health)
+	}
+	if _, err := m.Get("broken"); !errors.Is(err, gormx.ErrNotConnected) {
+		t.Errorf("get while reconnecting err = %v, want ErrNotConnected", err)
+	}
+
+	if err := m.Unregister("closed"); err != nil {
+		t.Fatalf("unregister: %v", err)
+	}
+	if _, ok := m.Health(context.Background())["closed"]; ok {
+		t.Error("unregistered instance still reported")
+	}
+}
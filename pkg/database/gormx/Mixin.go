@@ -0,0 +1,122 @@
+package gormx
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrOptimisticLock trả về khi Update một entity Versioned mà version trong DB đã bị thay đổi
+var ErrOptimisticLock = errors.New("gormx: optimistic lock conflict, record was modified or deleted")
+
+// Auditable nhúng vào entity để tự động ghi created_at/updated_at/created_by/updated_by.
+// created_by/updated_by lấy từ actor trong context (WithActor)
+type Auditable struct {
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
+	CreatedBy string    `gorm:"column:created_by;size:64"`
+	UpdatedBy string    `gorm:"column:updated_by;size:64"`
+}
+
+// SoftDeletable nhúng vào entity để xóa mềm, các truy vấn mặc định bỏ qua bản ghi đã xóa
+type SoftDeletable struct {
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;index"`
+}
+
+// Versioned nhúng vào entity để bật optimistic locking cho Repository.Update
+type Versioned struct {
+	Version int64 `gorm:"column:version;not null;default:1"`
+}
+
+type auditable interface{ audit() *Auditable }
+type softDeletable interface{ softDelete() *SoftDeletable }
+type versioned interface{ version() *Versioned }
+
+func (a *Auditable) audit() *Auditable              { return a }
+func (s *SoftDeletable) softDelete() *SoftDeletable { return s }
+func (v *Versioned) version() *Versioned            { return v }
+
+type actorKey struct{}
+
+// WithActor gắn danh tính người thực hiện (user id, service name...) vào ctx để ghi vào created_by/updated_by
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext trả về actor đã gắn bằng WithActor, rỗng nếu không có
+func ActorFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+var (
+	auditableType = reflect.TypeOf((*auditable)(nil)).Elem()
+	versionedType = reflect.TypeOf((*versioned)(nil)).Elem()
+)
+
+// registerMixinCallbacks đăng ký callback GORM cho Auditable và Versioned
+func registerMixinCallbacks(db *gorm.DB) error {
+	if err := db.Callback().Create().Before("gorm:create").Register("gormx:mixin_create", mixinBeforeCreate); err != nil {
+		return err
+	}
+	return db.Callback().Update().Before("gorm:update").Register("gormx:mixin_update", mixinBeforeUpdate)
+}
+
+func mixinBeforeCreate(db *gorm.DB) {
+	if db.Statement.Schema == nil || db.Error != nil {
+		return
+	}
+	modelType := reflect.PointerTo(db.Statement.Schema.ModelType)
+	isAuditable := modelType.Implements(auditableType)
+	isVersioned := modelType.Implements(versionedType)
+	if !isAuditable && !isVersioned {
+		return
+	}
+
+	actor := ActorFromContext(db.Statement.Context)
+	eachModel(db.Statement.ReflectValue, func(model any) {
+		if a, ok := model.(auditable); ok && actor != "" {
+			m := a.audit()
+			if m.CreatedBy == "" {
+				m.CreatedBy = actor
+			}
+			m.UpdatedBy = actor
+		}
+		if v, ok := model.(versioned); ok && v.version().Version == 0 {
+			v.version().Version = 1
+		}
+	})
+}
+
+func mixinBeforeUpdate(db *gorm.DB) {
+	if db.Statement.Schema == nil || db.Error != nil {
+		return
+	}
+	if !reflect.PointerTo(db.Statement.Schema.ModelType).Implements(auditableType) {
+		return
+	}
+	if actor := ActorFromContext(db.Statement.Context); actor != "" {
+		db.Statement.SetColumn("UpdatedBy", actor, true)
+	}
+}
+
+// eachModel gọi fn với con trỏ tới từng entity trong struct/slice mà GORM đang xử lý
+func eachModel(rv reflect.Value, fn func(model any)) {
+	rv = reflect.Indirect(rv)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			eachModel(rv.Index(i), fn)
+		}
+	case reflect.Struct:
+		if rv.CanAddr() {
+			fn(rv.Addr().Interface())
+		}
+	}
+}
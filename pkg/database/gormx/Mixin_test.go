@@ -0,0 +1,112 @@
+package gormx_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.io/xhkzeroone/goframex/pkg/database/gormx"
+	"github.io/xhkzeroone/goframex/pkg/database/gormx/gormxtest"
+	"gorm.io/gorm"
+)
+
+type document struct {
+	ID    uint `gorm:"primaryKey"`
+	Title string
+	gormx.Auditable
+	gormx.SoftDeletable
+	gormx.Versioned
+}
+
+func newDocuments(t *testing.T) (*gormx.DataSource, *gormx.Repository[document, uint]) {
+	t.Helper()
+	ds := gormxtest.New(t, gormxtest.WithModels(&document{}))
+	return ds, gormx.NewRepository[document, uint](ds)
+}
+
+func TestAuditableAndVersioned(t *testing.T) {
+	ds, repo := newDocuments(t)
+	ctx := gormxtest.Begin(t, ds)
+
+	doc := &document{Title: "draft"}
+	if err := repo.Insert(gormx.WithActor(ctx, "alice"), doc); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if doc.CreatedBy != "alice" || doc.UpdatedBy != "alice" || doc.Version != 1 || doc.CreatedAt.IsZero() {
+		t.Fatalf("after insert = %+v", doc)
+	}
+
+	doc.Title = "final"
+	doc.CreatedBy = "mallory"
+	if err := repo.Update(gormx.WithActor(ctx, "bob"), doc); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	stored, err := repo.FindByID(ctx, doc.ID)
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	// created_by không bị ghi đè khi Update
+	if stored.Title != "final" || stored.CreatedBy != "alice" || stored.UpdatedBy != "bob" || stored.Version != 2 {
+		t.Fatalf("after update = %+v", stored)
+	}
+}
+
+func TestVersionedRejectsStaleUpdate(t *testing.T) {
+	ds, repo := newDocuments(t)
+	ctx := gormxtest.Begin(t, ds)
+
+	doc := &document{Title: "v1"}
+	if err := repo.Insert(ctx, doc); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	stale := *doc
+
+	doc.Title = "v2"
+	if err := repo.Update(ctx, doc); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	stale.Title = "conflict"
+	if err := repo.Update(ctx, &stale); !errors.Is(err, gormx.ErrOptimisticLock) {
+		t.Fatalf("stale update err = %v, want ErrOptimisticLock", err)
+	}
+	if stale.Version != 1 {
+		t.Fatalf("stale version = %d, want unchanged 1", stale.Version)
+	}
+
+	// UpdateWhere cũng tăng version nên bản đang giữ bị từ chối
+	if _, err := repo.UpdateWhere(ctx, gormx.NewSpec(gormx.Eq("id", doc.ID)), map[string]any{"title": "bulk"}); err != nil {
+		t.Fatalf("update where: %v", err)
+	}
+	if err := repo.Update(ctx, doc); !errors.Is(err, gormx.ErrOptimisticLock) {
+		t.Fatalf("update after bulk err = %v, want ErrOptimisticLock", err)
+	}
+	gormxtest.AssertExists(t, ctx, ds, &document{}, "title = ? AND version = ?", "bulk", 3)
+}
+
+func TestSoftDeleteAndRestore(t *testing.T) {
+	ds, repo := newDocuments(t)
+	ctx := gormxtest.Begin(t, ds)
+
+	doc := &document{Title: "tmp"}
+	if err := repo.Insert(ctx, doc); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if err := repo.DeleteByID(ctx, doc.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	gormxtest.AssertNotExists(t, ctx, ds, &document{})
+	gormxtest.AssertRowCount(t, ctx, ds, "documents", 1, "deleted_at IS NOT NULL")
+	if _, err := repo.FindByID(ctx, doc.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("find deleted err = %v, want not found", err)
+	}
+	if items, err := repo.FindWithDeleted(ctx, gormx.NewSpec(gormx.Eq("id", doc.ID))); err != nil || len(items) != 1 {
+		t.Fatalf("find with deleted = %+v, %v", items, err)
+	}
+
+	if err := repo.Restore(ctx, doc.ID); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	gormxtest.AssertExists(t, ctx, ds, &document{}, "id = ?", doc.ID)
+	if err := repo.Restore(ctx, 999); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("restore missing err = %v, want not found", err)
+	}
+}
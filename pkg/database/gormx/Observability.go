@@ -0,0 +1,156 @@
+package gormx
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const instrumentationName = "github.io/xhkzeroone/goframex/pkg/database/gormx"
+
+const (
+	instanceSpanKey  = "gormx:span"
+	instanceStartKey = "gormx:start"
+	instanceCtxKey   = "gormx:parent_ctx"
+)
+
+// observability là plugin GORM tạo span và ghi metric (latency, số lỗi) theo bảng/thao tác
+// qua OpenTelemetry API, không có provider nào được cấu hình thì là no-op
+type observability struct {
+	tracer    trace.Tracer
+	duration  metric.Float64Histogram
+	errors    metric.Int64Counter
+	logParams bool
+}
+
+func newObservability(cfg *Config, tp trace.TracerProvider, mp metric.MeterProvider) (*observability, error) {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	if mp == nil {
+		mp = otel.GetMeterProvider()
+	}
+	meter := mp.Meter(instrumentationName)
+
+	duration, err := meter.Float64Histogram("db.client.operation.duration",
+		metric.WithDescription("Duration of database operations"),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	errCount, err := meter.Int64Counter("db.client.operation.errors",
+		metric.WithDescription("Number of failed database operations"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &observability{
+		tracer:    tp.Tracer(instrumentationName),
+		duration:  duration,
+		errors:    errCount,
+		logParams: cfg != nil && cfg.LogQueryParams,
+	}, nil
+}
+
+func (o *observability) Name() string {
+	return "gormx:observability"
+}
+
+func (o *observability) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("*").Register("gormx:before_create", o.before("create")),
+		cb.Create().After("*").Register("gormx:after_create", o.after("create")),
+		cb.Query().Before("*").Register("gormx:before_query", o.before("select")),
+		cb.Query().After("*").Register("gormx:after_query", o.after("select")),
+		cb.Update().Before("*").Register("gormx:before_update", o.before("update")),
+		cb.Update().After("*").Register("gormx:after_update", o.after("update")),
+		cb.Delete().Before("*").Register("gormx:before_delete", o.before("delete")),
+		cb.Delete().After("*").Register("gormx:after_delete", o.after("delete")),
+		cb.Row().Before("*").Register("gormx:before_row", o.before("row")),
+		cb.Row().After("*").Register("gormx:after_row", o.after("row")),
+		cb.Raw().Before("*").Register("gormx:before_raw", o.before("raw")),
+		cb.Raw().After("*").Register("gormx:after_raw", o.after("raw")),
+	)
+}
+
+func (o *observability) before(op string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx := db.Statement.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		spanCtx, span := o.tracer.Start(ctx, "gorm."+op, trace.WithSpanKind(trace.SpanKindClient))
+		db.InstanceSet(instanceCtxKey, db.Statement.Context)
+		db.InstanceSet(instanceSpanKey, span)
+		db.InstanceSet(instanceStartKey, time.Now())
+		db.Statement.Context = spanCtx
+	}
+}
+
+func (o *observability) after(op string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		v, ok := db.InstanceGet(instanceSpanKey)
+		if !ok {
+			return
+		}
+		span := v.(trace.Span)
+		start, _ := db.InstanceGet(instanceStartKey)
+		// Trả lại context ban đầu để các truy vấn sau không bị gắn vào span đã kết thúc
+		if parent, ok := db.InstanceGet(instanceCtxKey); ok {
+			db.Statement.Context, _ = parent.(context.Context)
+		}
+		ctx := db.Statement.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+		attrs := []attribute.KeyValue{
+			attribute.String("db.system", db.Dialector.Name()),
+			attribute.String("db.operation", op),
+			attribute.String("db.sql.table", table),
+		}
+
+		span.SetAttributes(attrs...)
+		span.SetAttributes(
+			attribute.String("db.statement", o.statement(db)),
+			attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+		)
+		failed := db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound)
+		if failed {
+			span.RecordError(db.Error)
+			span.SetStatus(codes.Error, db.Error.Error())
+		}
+		span.End()
+
+		if db.DryRun {
+			return
+		}
+		if t, ok := start.(time.Time); ok {
+			o.duration.Record(ctx, time.Since(t).Seconds(), metric.WithAttributes(attrs...))
+		}
+		if failed {
+			o.errors.Add(ctx, 1, metric.WithAttributes(attrs...))
+		}
+	}
+}
+
+// statement trả về câu SQL cho span, chỉ điền giá trị tham số khi bật LogQueryParams
+func (o *observability) statement(db *gorm.DB) string {
+	sql := db.Statement.SQL.String()
+	if o.logParams {
+		return db.Dialector.Explain(sql, db.Statement.Vars...)
+	}
+	return sql
+}
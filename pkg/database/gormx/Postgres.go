@@ -0,0 +1,200 @@
+package gormx
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// Các Condition trong file này chỉ dùng được với Postgres (driver pgx)
+
+var tsConfigPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// TSDocument là tsvector dùng cho full-text search: một cột tsvector có sẵn (TSVector)
+// hoặc to_tsvector tính từ các cột text (TSText)
+type TSDocument struct {
+	config  string
+	vector  string
+	columns []string
+}
+
+// TSVector dùng cột tsvector có sẵn (thường là generated column có GIN index).
+// config là cấu hình text search cho câu truy vấn, rỗng dùng default_text_search_config
+func TSVector(column, config string) TSDocument {
+	return TSDocument{config: config, vector: column}
+}
+
+// TSText tính to_tsvector(config, ...) từ các cột text nối bằng khoảng trắng, cột NULL coi như chuỗi rỗng.
+// config là bắt buộc để biểu thức khớp với index tạo bởi migrate.TextSearchIndex
+func TSText(config string, columns ...string) TSDocument {
+	return TSDocument{config: config, columns: columns}
+}
+
+// VectorColumn trả về cột tsvector nếu document được tạo bằng TSVector
+func (d TSDocument) VectorColumn() string {
+	return d.vector
+}
+
+// IndexExpression trả về biểu thức của document dùng cho CREATE INDEX, columns là tên cột DB
+func (d TSDocument) IndexExpression() string {
+	if d.vector != "" {
+		return quoteIdent(d.vector)
+	}
+	parts := make([]string, len(d.columns))
+	for i, c := range d.columns {
+		parts[i] = fmt.Sprintf("coalesce(%s, '')", quoteIdent(c))
+	}
+	return fmt.Sprintf("to_tsvector('%s', %s)", d.config, strings.Join(parts, " || ' ' || "))
+}
+
+func (d TSDocument) build(sch *schema.Schema) (clause.Expression, error) {
+	if d.config != "" && !tsConfigPattern.MatchString(d.config) {
+		return nil, fmt.Errorf("gormx: invalid text search config %q", d.config)
+	}
+	if d.vector != "" {
+		col, err := ResolveColumn(sch, d.vector)
+		if err != nil {
+			return nil, err
+		}
+		return clause.Expr{SQL: "?", Vars: []any{col}}, nil
+	}
+	if d.config == "" || len(d.columns) == 0 {
+		return nil, fmt.Errorf("gormx: TSText requires a config and at least one column")
+	}
+	vars := make([]any, len(d.columns))
+	parts := make([]string, len(d.columns))
+	for i, name := range d.columns {
+		col, err := ResolveColumn(sch, name)
+		if err != nil {
+			return nil, err
+		}
+		vars[i] = col
+		parts[i] = "coalesce(?, '')"
+	}
+	// config đã được kiểm tra nên ghi thẳng vào SQL để planner dùng được index biểu thức
+	return clause.Expr{SQL: fmt.Sprintf("to_tsvector('%s', %s)", d.config, strings.Join(parts, " || ' ' || ")), Vars: vars}, nil
+}
+
+func (d TSDocument) query(text string) clause.Expression {
+	if d.config == "" {
+		return clause.Expr{SQL: "plainto_tsquery(?)", Vars: []any{text}}
+	}
+	return clause.Expr{SQL: fmt.Sprintf("plainto_tsquery('%s', ?)", d.config), Vars: []any{text}}
+}
+
+// TextSearch lọc theo document @@ plainto_tsquery(query)
+func TextSearch(doc TSDocument, query string) Condition {
+	return ConditionFunc(func(sch *schema.Schema) (clause.Expression, error) {
+		vec, err := doc.build(sch)
+		if err != nil {
+			return nil, err
+		}
+		return clause.Expr{SQL: "? @@ ?", Vars: []any{vec, doc.query(query)}}, nil
+	})
+}
+
+// RankBy sắp xếp theo ts_rank(document, plainto_tsquery(query)) giảm dần, dùng với Spec.OrderByExpr
+func RankBy(doc TSDocument, query string) OrderExpression {
+	return OrderExpressionFunc(func(sch *schema.Schema) (clause.Expression, error) {
+		vec, err := doc.build(sch)
+		if err != nil {
+			return nil, err
+		}
+		return clause.Expr{SQL: "ts_rank(?, ?) DESC", Vars: []any{vec, doc.query(query)}}, nil
+	})
+}
+
+// JSONContains lọc cột jsonb chứa value (toán tử @>), value được mã hóa JSON
+func JSONContains(column string, value any) Condition {
+	return ConditionFunc(func(sch *schema.Schema) (clause.Expression, error) {
+		col, err := ResolveColumn(sch, column)
+		if err != nil {
+			return nil, err
+		}
+		data, ok := value.(json.RawMessage)
+		if !ok {
+			if data, err = json.Marshal(value); err != nil {
+				return nil, err
+			}
+		}
+		return clause.Expr{SQL: "? @> ?::jsonb", Vars: []any{col, string(data)}}, nil
+	})
+}
+
+// JSONPathEq so sánh giá trị dạng text tại path của cột jsonb (column #>> '{a,b}' = value)
+func JSONPathEq(column string, path []string, value any) Condition {
+	return columnCondition(column, func(col clause.Column) clause.Expression {
+		return clause.Expr{SQL: "? #>> ? = ?", Vars: []any{col, pgArray{path}, fmt.Sprint(value)}}
+	})
+}
+
+// JSONHasKey lọc cột jsonb có key ở cấp ngoài cùng (toán tử ?)
+func JSONHasKey(column, key string) Condition {
+	return columnCondition(column, func(col clause.Column) clause.Expression {
+		return clause.Expr{SQL: "? ? ?", Vars: []any{col, literalQuestionMark, key}}
+	})
+}
+
+// JSONHasAnyKey lọc cột jsonb có ít nhất một trong các key (toán tử ?|)
+func JSONHasAnyKey(column string, keys ...string) Condition {
+	return columnCondition(column, func(col clause.Column) clause.Expression {
+		return clause.Expr{SQL: "? ?| ?", Vars: []any{col, literalQuestionMark, pgArray{keys}}}
+	})
+}
+
+// ArrayHas lọc cột mảng có chứa value (value = ANY(column))
+func ArrayHas(column string, value any) Condition {
+	return columnCondition(column, func(col clause.Column) clause.Expression {
+		return clause.Expr{SQL: "? = ANY(?)", Vars: []any{value, col}}
+	})
+}
+
+// ArrayOverlaps lọc cột mảng có phần tử chung với values (toán tử &&), values là slice
+func ArrayOverlaps(column string, values any) Condition {
+	return arrayCondition(column, "&&", values)
+}
+
+// ArrayContainsAll lọc cột mảng chứa toàn bộ values (toán tử @>), values là slice
+func ArrayContainsAll(column string, values any) Condition {
+	return arrayCondition(column, "@>", values)
+}
+
+func arrayCondition(column, op string, values any) Condition {
+	return ConditionFunc(func(sch *schema.Schema) (clause.Expression, error) {
+		if k := reflect.ValueOf(values).Kind(); k != reflect.Slice && k != reflect.Array {
+			return nil, fmt.Errorf("gormx: %s on %s requires a slice, got %T", op, column, values)
+		}
+		col, err := ResolveColumn(sch, column)
+		if err != nil {
+			return nil, err
+		}
+		return clause.Expr{SQL: "? " + op + " ?", Vars: []any{col, pgArray{values}}}, nil
+	})
+}
+
+// literalQuestionMark ghi dấu ? vào SQL mà không bị GORM hiểu là placeholder
+var literalQuestionMark = clause.Expr{SQL: "?"}
+
+// pgArray truyền slice thành một tham số mảng cho pgx thay vì để GORM tách thành danh sách (a, b, c)
+type pgArray struct {
+	values any
+}
+
+func (a pgArray) Value() (driver.Value, error) {
+	return a.values, nil
+}
+
+// quoteIdent đặt tên cột/bảng trong dấu nháy kép, hỗ trợ dạng schema.table
+func quoteIdent(name string) string {
+	parts := strings.Split(name, ".")
+	for i, p := range parts {
+		parts[i] = `"` + strings.ReplaceAll(p, `"`, `""`) + `"`
+	}
+	return strings.Join(parts, ".")
+}
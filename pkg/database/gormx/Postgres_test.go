@@ -0,0 +1,136 @@
+package gormx_test
+
+import (
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.io/xhkzeroone/goframex/pkg/database/gormx"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+type article struct {
+	ID     uint
+	Title  string
+	Body   string
+	Search string `gorm:"column:search_vector;type:tsvector"`
+	Attrs  string `gorm:"type:jsonb"`
+	Tags   string `gorm:"type:text[]"`
+}
+
+// dryRunPostgres dựng SQL bằng dialector Postgres mà không cần kết nối
+func dryRunPostgres(t *testing.T) (*gorm.DB, *schema.Schema) {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1 port=1 user=test dbname=test"}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	sch, err := schema.Parse(&article{}, &sync.Map{}, db.NamingStrategy)
+	if err != nil {
+		t.Fatalf("parse schema: %v", err)
+	}
+	return db, sch
+}
+
+// driverValues trả về giá trị mà driver nhận được cho từng tham số
+func driverValues(t *testing.T, vars []any) []any {
+	t.Helper()
+	out := make([]any, len(vars))
+	for i, v := range vars {
+		out[i] = v
+		if valuer, ok := v.(driver.Valuer); ok {
+			value, err := valuer.Value()
+			if err != nil {
+				t.Fatalf("value of %v: %v", v, err)
+			}
+			out[i] = value
+		}
+	}
+	return out
+}
+
+func TestPostgresConditionSQL(t *testing.T) {
+	db, sch := dryRunPostgres(t)
+	const prefix = `SELECT * FROM "articles" WHERE `
+	for _, tc := range []struct {
+		name string
+		cond gormx.Condition
+		sql  string
+		vars []any
+	}{
+		{"JSONContains", gormx.JSONContains("attrs", map[string]int{"a": 1}), `"articles"."attrs" @> $1::jsonb`, []any{`{"a":1}`}},
+		{"JSONHasKey", gormx.JSONHasKey("attrs", "a"), `"articles"."attrs" ? $1`, []any{"a"}},
+		{"JSONHasAnyKey", gormx.JSONHasAnyKey("Attrs", "a", "b"), `"articles"."attrs" ?| $1`, []any{[]string{"a", "b"}}},
+		{"JSONPathEq", gormx.JSONPathEq("attrs", []string{"a", "b"}, 3), `"articles"."attrs" #>> $1 = $2`, []any{[]string{"a", "b"}, "3"}},
+		{"ArrayHas", gormx.ArrayHas("tags", "go"), `$1 = ANY("articles"."tags")`, []any{"go"}},
+		{"ArrayOverlaps", gormx.ArrayOverlaps("tags", []string{"go", "db"}), `"articles"."tags" && $1`, []any{[]string{"go", "db"}}},
+		{"ArrayContainsAll", gormx.ArrayContainsAll("tags", []string{"go"}), `"articles"."tags" @> $1`, []any{[]string{"go"}}},
+		{
+			"TextSearch TSText",
+			gormx.TextSearch(gormx.TSText("english", "title", "body"), "hello"),
+			`to_tsvector('english', coalesce("articles"."title", '') || ' ' || coalesce("articles"."body", '')) @@ plainto_tsquery('english', $1)`,
+			[]any{"hello"},
+		},
+		{"TextSearch TSVector", gormx.TextSearch(gormx.TSVector("search_vector", ""), "hello"), `"articles"."search_vector" @@ plainto_tsquery($1)`, []any{"hello"}},
+	} {
+		expr, err := tc.cond.Build(sch)
+		if err != nil {
+			t.Errorf("%s: build: %v", tc.name, err)
+			continue
+		}
+		stmt := db.Model(&article{}).Where(expr).Find(&[]article{}).Statement
+		if got := stmt.SQL.String(); got != prefix+tc.sql {
+			t.Errorf("%s:\n got %s\nwant %s", tc.name, got, prefix+tc.sql)
+		}
+		// Slice được truyền nguyên là một tham số mảng, không bị tách thành danh sách
+		if got := driverValues(t, stmt.Vars); !reflect.DeepEqual(got, tc.vars) {
+			t.Errorf("%s: vars = %#v, want %#v", tc.name, got, tc.vars)
+		}
+	}
+}
+
+func TestPostgresRankAndIndexExpression(t *testing.T) {
+	db, sch := dryRunPostgres(t)
+	doc := gormx.TSText("simple", "title", "body")
+
+	order, err := gormx.RankBy(gormx.TSVector("Search", "english"), "go").BuildOrder(sch)
+	if err != nil {
+		t.Fatalf("rank: %v", err)
+	}
+	stmt := db.Model(&article{}).Clauses(clause.OrderBy{Expression: order}).Find(&[]article{}).Statement
+	want := `SELECT * FROM "articles" ORDER BY ts_rank("articles"."search_vector", plainto_tsquery('english', $1)) DESC`
+	if got := stmt.SQL.String(); got != want {
+		t.Errorf("rank:\n got %s\nwant %s", got, want)
+	}
+
+	if got, want := doc.IndexExpression(), `to_tsvector('simple', coalesce("title", '') || ' ' || coalesce("body", ''))`; got != want {
+		t.Errorf("index expression = %s, want %s", got, want)
+	}
+	if got := gormx.TSVector("search_vector", "").IndexExpression(); got != `"search_vector"` {
+		t.Errorf("vector index expression = %s", got)
+	}
+}
+
+func TestPostgresConditionErrors(t *testing.T) {
+	_, sch := dryRunPostgres(t)
+	for name, cond := range map[string]gormx.Condition{
+		"invalid config":  gormx.TextSearch(gormx.TSText("english'); DROP TABLE articles; --", "title"), "x"),
+		"no columns":      gormx.TextSearch(gormx.TSText("english"), "x"),
+		"not a slice":     gormx.ArrayOverlaps("tags", "go"),
+		"unknown column":  gormx.JSONHasKey("missing", "a"),
+		"unknown ts text": gormx.TextSearch(gormx.TSText("english", "missing"), "x"),
+	} {
+		if _, err := cond.Build(sch); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if _, err := gormx.ArrayHas("missing", 1).Build(sch); !errors.Is(err, gormx.ErrUnknownColumn) {
+		t.Errorf("unknown column err = %v, want ErrUnknownColumn", err)
+	}
+}
@@ -0,0 +1,103 @@
+package gormx
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.io/xhkzeroone/goframex/pkg/logger/logrusx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const defaultSlowThreshold = 200 * time.Millisecond
+
+// QueryLogger là logger GORM ghi qua logrusx kèm requestId trong context.
+// Mặc định chỉ log lỗi và truy vấn chậm, LogMode(logger.Info) (Config.Debug) log mọi truy vấn
+type QueryLogger struct {
+	level         logger.LogLevel
+	slowThreshold time.Duration
+	logParams     bool
+}
+
+// NewQueryLogger tạo logger theo Config.SlowThreshold, Config.LogQueryParams
+func NewQueryLogger(cfg *Config) *QueryLogger {
+	l := &QueryLogger{level: logger.Warn, slowThreshold: defaultSlowThreshold}
+	if cfg != nil {
+		if cfg.SlowThreshold > 0 {
+			l.slowThreshold = cfg.SlowThreshold
+		}
+		l.logParams = cfg.LogQueryParams
+	}
+	return l
+}
+
+func (l *QueryLogger) LogMode(level logger.LogLevel) logger.Interface {
+	out := *l
+	out.level = level
+	return &out
+}
+
+func (l *QueryLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Info {
+		queryLogEntry(ctx).Infof(msg, args...)
+	}
+}
+
+func (l *QueryLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Warn {
+		queryLogEntry(ctx).Warnf(msg, args...)
+	}
+}
+
+func (l *QueryLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Error {
+		queryLogEntry(ctx).Errorf(msg, args...)
+	}
+}
+
+func (l *QueryLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.level <= logger.Silent {
+		return
+	}
+	elapsed := time.Since(begin)
+	slow := l.slowThreshold > 0 && elapsed > l.slowThreshold
+
+	switch {
+	case err != nil && l.level >= logger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		sql, rows := fc()
+		queryLogEntry(ctx).WithFields(queryFields(elapsed, rows)).WithError(err).Errorf("query failed: %s", sql)
+	case slow && l.level >= logger.Warn:
+		sql, rows := fc()
+		queryLogEntry(ctx).WithFields(queryFields(elapsed, rows)).Warnf("slow query (>= %s): %s", l.slowThreshold, sql)
+	case l.level >= logger.Info:
+		sql, rows := fc()
+		queryLogEntry(ctx).WithFields(queryFields(elapsed, rows)).Infof("query: %s", sql)
+	}
+}
+
+// ParamsFilter ẩn giá trị tham số khỏi câu SQL được log, trừ khi bật LogQueryParams
+func (l *QueryLogger) ParamsFilter(_ context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	if l.logParams {
+		return sql, params
+	}
+	return sql, nil
+}
+
+func queryLogEntry(ctx context.Context) *logrus.Entry {
+	if logrusx.Log == nil {
+		return logrus.NewEntry(logrus.StandardLogger())
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return logrusx.WithContext(ctx)
+}
+
+func queryFields(elapsed time.Duration, rows int64) logrus.Fields {
+	return logrus.Fields{
+		"duration_ms": float64(elapsed.Microseconds()) / 1000,
+		"rows":        rows,
+	}
+}
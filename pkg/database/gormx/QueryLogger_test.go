@@ -0,0 +1,92 @@
+package gormx_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.io/xhkzeroone/goframex/pkg/database/gormx"
+	"github.io/xhkzeroone/goframex/pkg/database/gormx/gormxtest"
+	"github.io/xhkzeroone/goframex/pkg/logger/logrusx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func captureLogs(t *testing.T) *test.Hook {
+	t.Helper()
+	log, hook := test.NewNullLogger()
+	log.SetLevel(logrus.DebugLevel)
+	prev := logrusx.Log
+	logrusx.Log = log
+	t.Cleanup(func() { logrusx.Log = prev })
+	return hook
+}
+
+func TestQueryLoggerSlowThreshold(t *testing.T) {
+	hook := captureLogs(t)
+	ctx := context.Background()
+	sql := func() (string, int64) { return "SELECT * FROM products", 3 }
+	trace := func(l logger.Interface, elapsed time.Duration, err error) *logrus.Entry {
+		t.Helper()
+		hook.Reset()
+		l.Trace(ctx, time.Now().Add(-elapsed), sql, err)
+		if len(hook.AllEntries()) > 1 {
+			t.Fatalf("got %d log entries, want at most 1", len(hook.AllEntries()))
+		}
+		return hook.LastEntry()
+	}
+
+	l := gormx.NewQueryLogger(&gormx.Config{SlowThreshold: 50 * time.Millisecond})
+	if e := trace(l, 10*time.Millisecond, nil); e != nil {
+		t.Errorf("fast query logged: %s", e.Message)
+	}
+	e := trace(l, 100*time.Millisecond, nil)
+	if e == nil || e.Level != logrus.WarnLevel || !strings.Contains(e.Message, "slow query (>= 50ms): SELECT * FROM products") {
+		t.Fatalf("slow query entry = %+v", e)
+	}
+	if e.Data["rows"] != int64(3) || e.Data["duration_ms"].(float64) < 100 {
+		t.Errorf("slow query fields = %v", e.Data)
+	}
+
+	// Ngưỡng mặc định 200ms
+	if e := trace(gormx.NewQueryLogger(&gormx.Config{}), 100*time.Millisecond, nil); e != nil {
+		t.Errorf("query under default threshold logged: %s", e.Message)
+	}
+	if e := trace(gormx.NewQueryLogger(nil), 300*time.Millisecond, nil); e == nil || e.Level != logrus.WarnLevel {
+		t.Errorf("query over default threshold entry = %+v", e)
+	}
+
+	if e := trace(l, time.Millisecond, errors.New("boom")); e == nil || e.Level != logrus.ErrorLevel {
+		t.Errorf("failed query entry = %+v", e)
+	}
+	if e := trace(l, time.Millisecond, gorm.ErrRecordNotFound); e != nil {
+		t.Errorf("record not found logged: %s", e.Message)
+	}
+	if e := trace(l.LogMode(logger.Info), time.Millisecond, nil); e == nil || e.Level != logrus.InfoLevel {
+		t.Errorf("debug mode entry = %+v", e)
+	}
+	if e := trace(l.LogMode(logger.Silent), time.Second, nil); e != nil {
+		t.Errorf("silent mode logged: %s", e.Message)
+	}
+}
+
+func TestQueryLoggerLogsSlowQueries(t *testing.T) {
+	ds := gormxtest.New(t, gormxtest.WithModels(&product{}), gormxtest.WithConfig(&gormx.Config{SlowThreshold: time.Nanosecond}))
+	hook := captureLogs(t)
+
+	if err := ds.DB.WithContext(context.Background()).Where("name = ?", "secret-name").Find(&[]product{}).Error; err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	e := hook.LastEntry()
+	if e == nil || e.Level != logrus.WarnLevel || !strings.Contains(e.Message, "slow query") {
+		t.Fatalf("entry = %+v, want slow query warning", e)
+	}
+	// Tham số không được log khi tắt LogQueryParams
+	if strings.Contains(e.Message, "secret-name") {
+		t.Errorf("query params leaked into log: %s", e.Message)
+	}
+}
@@ -0,0 +1,249 @@
+package gormx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ReplicaRoundRobin   = "round_robin"
+	ReplicaRandom       = "random"
+	ReplicaLeastLatency = "least_latency"
+
+	defaultReplicaHealthCheckInterval = 10 * time.Second
+	replicaPingTimeout                = 3 * time.Second
+)
+
+type replica struct {
+	name string
+	// db là nil cho tới khi mở kết nối thành công, open được gọi lại ở mỗi lần health check
+	db      atomic.Pointer[gorm.DB]
+	open    func() (*gorm.DB, error)
+	healthy atomic.Bool
+	// latency là trung bình trượt (EWMA) thời gian ping, tính bằng nanosecond
+	latency atomic.Int64
+}
+
+// ReplicaStatus mô tả trạng thái hiện tại của một replica
+type ReplicaStatus struct {
+	Name    string        `json:"name"`
+	Healthy bool          `json:"healthy"`
+	Latency time.Duration `json:"latency"`
+}
+
+type replicaSet struct {
+	replicas []*replica
+	policy   string
+	counter  atomic.Uint64
+	// pool là cấu hình pool hiện hành, replica mở lại sau khi ReloadPool cũng dùng giá trị mới
+	pool atomic.Pointer[PoolConfig]
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// openReplicas mở các replica với cùng callback tenant/mixin như primary (t là tenancy của DataSource)
+func openReplicas(cfg *Config, baseCfg gorm.Config, debug bool, t *tenancy) (*replicaSet, error) {
+	policy := cfg.ReplicaPolicy
+	switch policy {
+	case "":
+		policy = ReplicaRoundRobin
+	case ReplicaRoundRobin, ReplicaRandom, ReplicaLeastLatency:
+	default:
+		return nil, fmt.Errorf("unsupported replica policy: %q", cfg.ReplicaPolicy)
+	}
+
+	rs := &replicaSet{policy: policy, stop: make(chan struct{})}
+	pool := cfg.poolConfig()
+	rs.pool.Store(&pool)
+	for i, rc := range cfg.Replicas {
+		replicaCfg := cfg.replicaConfig(rc)
+		dialector, err := NewDialector(replicaCfg)
+		if err != nil {
+			_ = rs.close()
+			return nil, err
+		}
+
+		// Replica lỗi lúc khởi động không chặn ứng dụng, health check sẽ mở lại và đưa vào khi phục hồi
+		r := &replica{
+			name: fmt.Sprintf("replica-%d", i),
+			open: func() (*gorm.DB, error) {
+				gormCfg := baseCfg
+				gormCfg.DisableAutomaticPing = true
+				db, err := openDB(dialector, &gormCfg, replicaCfg)
+				if err != nil {
+					return nil, err
+				}
+				// Thiếu callback tenant thì truy vấn đọc trên replica không được lọc theo tenant
+				if err := errors.Join(registerMixinCallbacks(db), registerTenantCallbacks(db, t)); err != nil {
+					if sqlDB, dbErr := db.DB(); dbErr == nil {
+						_ = sqlDB.Close()
+					}
+					return nil, err
+				}
+				if sqlDB, err := db.DB(); err == nil {
+					applyPool(sqlDB, *rs.pool.Load())
+				}
+				if debug {
+					db = db.Debug()
+				}
+				return db, nil
+			},
+		}
+		rs.replicas = append(rs.replicas, r)
+		rs.check(r)
+	}
+
+	interval := cfg.ReplicaHealthCheckInterval
+	if interval <= 0 {
+		interval = defaultReplicaHealthCheckInterval
+	}
+	rs.wg.Add(1)
+	go rs.healthLoop(interval)
+	return rs, nil
+}
+
+// pick chọn replica theo policy, trả về nil nếu không còn replica nào khỏe
+func (s *replicaSet) pick() *replica {
+	healthy := make([]*replica, 0, len(s.replicas))
+	for _, r := range s.replicas {
+		if r.healthy.Load() {
+			healthy = append(healthy, r)
+		}
+	}
+	if len(healthy) == 0 {
+		return nil
+	}
+
+	switch s.policy {
+	case ReplicaRandom:
+		return healthy[rand.Intn(len(healthy))]
+	case ReplicaLeastLatency:
+		best := healthy[0]
+		for _, r := range healthy[1:] {
+			if r.latency.Load() < best.latency.Load() {
+				best = r
+			}
+		}
+		return best
+	default:
+		n := s.counter.Add(1) - 1
+		return healthy[n%uint64(len(healthy))]
+	}
+}
+
+func (s *replicaSet) healthLoop(interval time.Duration) {
+	defer s.wg.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			for _, r := range s.replicas {
+				s.check(r)
+			}
+		}
+	}
+}
+
+// check ping replica, cập nhật trạng thái và độ trễ
+func (s *replicaSet) check(r *replica) {
+	ctx, cancel := context.WithTimeout(context.Background(), replicaPingTimeout)
+	defer cancel()
+
+	db := r.db.Load()
+	if db == nil {
+		opened, err := r.open()
+		if err != nil {
+			log.Printf("gormx replica %s can not be opened: %v", r.name, err)
+			return
+		}
+		r.db.Store(opened)
+		db = opened
+	}
+
+	start := time.Now()
+	err := pingDB(ctx, db)
+	elapsed := time.Since(start)
+
+	if err != nil {
+		if r.healthy.Swap(false) {
+			log.Printf("gormx replica %s is unhealthy, evicted from read pool: %v", r.name, err)
+		}
+		return
+	}
+
+	prev := r.latency.Load()
+	if prev == 0 {
+		r.latency.Store(int64(elapsed))
+	} else {
+		r.latency.Store((prev*7 + int64(elapsed)*3) / 10)
+	}
+	if !r.healthy.Swap(true) {
+		log.Printf("gormx replica %s is healthy, added to read pool", r.name)
+	}
+}
+
+func (s *replicaSet) status() []ReplicaStatus {
+	out := make([]ReplicaStatus, 0, len(s.replicas))
+	for _, r := range s.replicas {
+		out = append(out, ReplicaStatus{
+			Name:    r.name,
+			Healthy: r.healthy.Load(),
+			Latency: time.Duration(r.latency.Load()),
+		})
+	}
+	return out
+}
+
+func (s *replicaSet) close() error {
+	select {
+	case <-s.stop:
+	default:
+		close(s.stop)
+	}
+	s.wg.Wait()
+
+	var errs []error
+	for _, r := range s.replicas {
+		db := r.db.Load()
+		if db == nil {
+			continue
+		}
+		sqlDB, err := db.DB()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := sqlDB.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close %s: %w", r.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func pingDB(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// ReplicaStatus trả về trạng thái các replica (rỗng nếu không cấu hình replica)
+func (p *DataSource) ReplicaStatus() []ReplicaStatus {
+	if p.replicas == nil {
+		return nil
+	}
+	return p.replicas.status()
+}
@@ -0,0 +1,115 @@
+package gormx_test
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.io/xhkzeroone/goframex/pkg/database/gormx"
+	"gorm.io/gorm"
+)
+
+type shelf struct {
+	ID     uint `gorm:"primaryKey"`
+	Source string
+}
+
+// openWithReplica mở primary và một replica là hai file SQLite riêng, replica có sẵn dòng "replica"
+func openWithReplica(t *testing.T) (*gormx.DataSource, *gormx.Repository[shelf, uint]) {
+	t.Helper()
+	dir := t.TempDir()
+	primaryPath, replicaPath := filepath.Join(dir, "primary.db"), filepath.Join(dir, "replica.db")
+
+	seed, err := gorm.Open(sqlite.Open(replicaPath), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open replica: %v", err)
+	}
+	if err := seed.AutoMigrate(&shelf{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := seed.Create(&shelf{ID: 1, Source: "replica"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if sqlDB, err := seed.DB(); err == nil {
+		_ = sqlDB.Close()
+	}
+
+	ds, err := gormx.Open(&gormx.Config{
+		Driver:               gormx.DriverSQLite,
+		DSN:                  primaryPath,
+		CursorSecret:         "test",
+		Replicas:             []gormx.ReplicaConfig{{DSN: replicaPath}},
+		ReadAfterWriteWindow: time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { _ = ds.Close() })
+	if err := ds.AutoMigrate(&shelf{}); err != nil {
+		t.Fatal(err)
+	}
+	return ds, gormx.NewRepository[shelf, uint](ds)
+}
+
+func sources(t *testing.T, ctx context.Context, repo *gormx.Repository[shelf, uint]) []string {
+	t.Helper()
+	items, err := repo.FindWhere(ctx, "1 = 1")
+	if err != nil {
+		t.Fatalf("find: %v", err)
+	}
+	out := make([]string, len(items))
+	for i, s := range items {
+		out[i] = s.Source
+	}
+	return out
+}
+
+func TestReplicaRouting(t *testing.T) {
+	ds, repo := openWithReplica(t)
+	ctx := context.Background()
+
+	if status := ds.ReplicaStatus(); len(status) != 1 || !status[0].Healthy {
+		t.Fatalf("replica status = %+v", status)
+	}
+	if got := sources(t, ctx, repo); !slices.Equal(got, []string{"replica"}) {
+		t.Errorf("read = %v, want replica", got)
+	}
+
+	if err := repo.Insert(ctx, &shelf{ID: 2, Source: "primary"}); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if got := sources(t, ctx, repo); !slices.Equal(got, []string{"replica"}) {
+		t.Errorf("read after write without session = %v, want replica", got)
+	}
+	if got := sources(t, gormx.UsePrimary(ctx), repo); !slices.Equal(got, []string{"primary"}) {
+		t.Errorf("UsePrimary read = %v, want primary", got)
+	}
+
+	err := gormx.Transactional(ctx, ds, func(ctx context.Context) error {
+		if got := sources(t, ctx, repo); !slices.Equal(got, []string{"primary"}) {
+			t.Errorf("read in transaction = %v, want primary", got)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("transaction: %v", err)
+	}
+}
+
+func TestReplicaReadYourWrites(t *testing.T) {
+	_, repo := openWithReplica(t)
+	ctx := gormx.WithReadYourWrites(context.Background())
+
+	if got := sources(t, ctx, repo); !slices.Equal(got, []string{"replica"}) {
+		t.Errorf("read before write = %v, want replica", got)
+	}
+	if err := repo.Insert(ctx, &shelf{ID: 2, Source: "primary"}); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if got := sources(t, ctx, repo); !slices.Equal(got, []string{"primary"}) {
+		t.Errorf("read after write in session = %v, want primary", got)
+	}
+}
@@ -3,17 +3,20 @@ package gormx
 import (
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/schema"
 )
 
 type Page[T any] struct {
-	Items      []T   `json:"items"`
-	TotalCount int64 `json:"totalCount"`
-	Page       int   `json:"page"`
-	PageSize   int   `json:"pageSize"`
+	Items      []T     `json:"items"`
+	TotalCount int64   `json:"totalCount"`
+	Page       int     `json:"page"`
+	PageSize   int     `json:"pageSize"`
+	Sort       []Order `json:"sort,omitempty"`
 }
 
 // IRepository định nghĩa interface cho repository generic
@@ -26,12 +29,22 @@ type IRepository[T any, ID comparable] interface {
 	FindOneWhere(ctx context.Context, query any, args ...any) (*T, error)
 	Update(ctx context.Context, entity *T) error
 	DeleteByID(ctx context.Context, id ID) error
-	ListAll(ctx context.Context) ([]T, error)
-	Count(ctx context.Context) (int64, error)
+	Restore(ctx context.Context, id ID) error
+	FindWithDeleted(ctx context.Context, spec *Spec) ([]T, error)
+	Count(ctx context.Context, specs ...*Spec) (int64, error)
 	CountBy(ctx context.Context, query any, args ...any) (int64, error)
 	RawQuery(ctx context.Context, query string, args ...any) ([]T, error)
 	Exists(ctx context.Context, query any, args ...any) (bool, error)
 	Pageable(ctx context.Context, page int, pageSize int, query any, args ...any) (*Page[T], error)
+	Find(ctx context.Context, spec *Spec) ([]T, error)
+	FindOne(ctx context.Context, spec *Spec) (*T, error)
+	Page(ctx context.Context, spec *Spec, page int, pageSize int) (*Page[T], error)
+	Cursor(ctx context.Context, spec *Spec, after string, limit int, opts ...CursorOption) (*CursorPage[T], error)
+	InsertBatch(ctx context.Context, items []T, batchSize int) error
+	Upsert(ctx context.Context, items []T, conflictColumns []string, updateColumns []string) error
+	UpdateWhere(ctx context.Context, spec *Spec, values map[string]any) (int64, error)
+	DeleteWhere(ctx context.Context, spec *Spec) (int64, error)
+	Stream(ctx context.Context, spec *Spec, fn func(batch []T) error, opts ...StreamOption) error
 }
 
 // Repository là struct generic cho thao tác DB với GORM
@@ -49,13 +62,17 @@ func NewRepository[T any, ID comparable](db *DataSource) *Repository[T, ID] {
 
 // Insert thêm entity vào DB
 func (r *Repository[T, ID]) Insert(ctx context.Context, entity *T) error {
-	return r.WithContext(ctx).Model(new(T)).Create(entity).Error
+	return r.Writer(ctx).Model(new(T)).Create(entity).Error
 }
 
 // FindByID tìm entity theo ID, trả về nil nếu không tìm thấy
 func (r *Repository[T, ID]) FindByID(ctx context.Context, id ID) (*T, error) {
+	cond, err := r.primaryKeyEq(id)
+	if err != nil {
+		return nil, err
+	}
 	entity := new(T)
-	err := r.WithContext(ctx).Model(new(T)).First(entity, id).Error
+	err = r.Reader(ctx).Model(new(T)).Where(cond).First(entity).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, err
@@ -68,14 +85,14 @@ func (r *Repository[T, ID]) FindByID(ctx context.Context, id ID) (*T, error) {
 // FindWhere tìm danh sách entity theo điều kiện
 func (r *Repository[T, ID]) FindWhere(ctx context.Context, query any, args ...any) ([]T, error) {
 	var list []T
-	err := r.WithContext(ctx).Model(new(T)).Where(query, args...).Find(&list).Error
+	err := r.Reader(ctx).Model(new(T)).Where(query, args...).Find(&list).Error
 	return list, err
 }
 
 // FindOneWhere tìm một entity theo điều kiện
 func (r *Repository[T, ID]) FindOneWhere(ctx context.Context, query any, args ...any) (*T, error) {
 	var item T
-	err := r.WithContext(ctx).Model(new(T)).Where(query, args...).First(&item).Error
+	err := r.Reader(ctx).Model(new(T)).Where(query, args...).First(&item).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, err
@@ -85,48 +102,110 @@ func (r *Repository[T, ID]) FindOneWhere(ctx context.Context, query any, args ..
 	return &item, nil
 }
 
-// Update cập nhật entity
+// Update cập nhật entity. Với entity Versioned, trả về ErrOptimisticLock nếu version trong DB đã thay đổi
 func (r *Repository[T, ID]) Update(ctx context.Context, entity *T) error {
-	return r.WithContext(ctx).Model(new(T)).Save(entity).Error
+	v, ok := any(entity).(versioned)
+	if !ok {
+		db := r.Writer(ctx).Model(new(T))
+		// Save tự insert (ON CONFLICT ghi đè) khi update không khớp dòng nào, với entity TenantScoped
+		// điều đó chuyển dòng của tenant khác sang tenant hiện tại nên chỉ update
+		if _, scoped := any(entity).(tenantScoped); scoped {
+			db = db.Select("*")
+		}
+		return db.Save(entity).Error
+	}
+
+	current := v.version().Version
+	v.version().Version = current + 1
+	db := r.Writer(ctx).Model(entity).
+		Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: "version"}, Value: current}).
+		Select("*")
+	// Không ghi đè thông tin tạo bản ghi
+	if _, ok := any(entity).(auditable); ok {
+		db = db.Omit("created_at", "created_by")
+	}
+	res := db.Updates(entity)
+	if res.Error == nil && res.RowsAffected == 0 {
+		res.Error = ErrOptimisticLock
+	}
+	if res.Error != nil {
+		v.version().Version = current
+	}
+	return res.Error
 }
 
 // DeleteByID xóa entity theo ID
 func (r *Repository[T, ID]) DeleteByID(ctx context.Context, id ID) error {
-	return r.WithContext(ctx).Model(new(T)).Delete(new(T), id).Error
+	cond, err := r.primaryKeyEq(id)
+	if err != nil {
+		return err
+	}
+	return r.Writer(ctx).Model(new(T)).Where(cond).Delete(new(T)).Error
 }
 
-// ListAll lấy tất cả entity
-func (r *Repository[T, ID]) ListAll(ctx context.Context) ([]T, error) {
+// Restore khôi phục entity SoftDeletable đã bị xóa mềm
+func (r *Repository[T, ID]) Restore(ctx context.Context, id ID) error {
+	if _, ok := any(new(T)).(softDeletable); !ok {
+		return errors.New("gormx: restore requires entity embedding gormx.SoftDeletable")
+	}
+	cond, err := r.primaryKeyEq(id)
+	if err != nil {
+		return err
+	}
+
+	res := r.Writer(ctx).Unscoped().Model(new(T)).Where(cond).Update("deleted_at", nil)
+	if res.Error == nil && res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return res.Error
+}
+
+// FindWithDeleted tìm theo Spec, bao gồm cả bản ghi đã bị xóa mềm
+func (r *Repository[T, ID]) FindWithDeleted(ctx context.Context, spec *Spec) ([]T, error) {
+	db, err := r.withSpec(r.Reader(ctx).Unscoped(), spec)
+	if err != nil {
+		return nil, err
+	}
 	var list []T
-	err := r.WithContext(ctx).Model(new(T)).Find(&list).Error
+	err = db.Find(&list).Error
 	return list, err
 }
 
-// Count đếm tổng số entity
-func (r *Repository[T, ID]) Count(ctx context.Context) (int64, error) {
+// Count đếm số entity, có thể truyền Spec để lọc
+func (r *Repository[T, ID]) Count(ctx context.Context, specs ...*Spec) (int64, error) {
+	sch, err := r.schema()
+	if err != nil {
+		return 0, err
+	}
+	db := r.Reader(ctx).Model(new(T))
+	for _, spec := range specs {
+		if db, err = spec.applyFilter(db, sch); err != nil {
+			return 0, err
+		}
+	}
 	var count int64
-	err := r.WithContext(ctx).Model(new(T)).Count(&count).Error
+	err = db.Count(&count).Error
 	return count, err
 }
 
 // CountBy đếm entity theo điều kiện
 func (r *Repository[T, ID]) CountBy(ctx context.Context, query any, args ...any) (int64, error) {
 	var count int64
-	err := r.WithContext(ctx).Model(new(T)).Where(query, args...).Count(&count).Error
+	err := r.Reader(ctx).Model(new(T)).Where(query, args...).Count(&count).Error
 	return count, err
 }
 
 // RawQuery thực thi truy vấn SQL thô
 func (r *Repository[T, ID]) RawQuery(ctx context.Context, query string, args ...any) ([]T, error) {
 	var results []T
-	err := r.WithContext(ctx).Raw(query, args...).Scan(&results).Error
+	err := r.Reader(ctx).Raw(query, args...).Scan(&results).Error
 	return results, err
 }
 
 // Exists kiểm tra có entity nào thỏa điều kiện không (an toàn, không dùng raw SQL)
 func (r *Repository[T, ID]) Exists(ctx context.Context, query any, args ...any) (bool, error) {
 	var count int64
-	err := r.WithContext(ctx).Model(new(T)).Where(query, args...).Count(&count).Error
+	err := r.Reader(ctx).Model(new(T)).Where(query, args...).Count(&count).Error
 	return count > 0, err
 }
 
@@ -134,15 +213,17 @@ func (r *Repository[T, ID]) Exists(ctx context.Context, query any, args ...any)
 func (r *Repository[T, ID]) Pageable(ctx context.Context, page int, pageSize int, query any, args ...any) (*Page[T], error) {
 	var items []T
 	var total int64
+	// Dùng cùng một kết nối đọc cho cả count và dữ liệu
+	db := r.Reader(ctx)
 
 	// Đếm tổng số bản ghi
-	if err := r.WithContext(ctx).Model(new(T)).Where(query, args...).Count(&total).Error; err != nil {
+	if err := db.Model(new(T)).Where(query, args...).Count(&total).Error; err != nil {
 		return nil, err
 	}
 
 	// Lấy dữ liệu theo trang
 	offset := (page - 1) * pageSize
-	if err := r.WithContext(ctx).Where(query, args...).Limit(pageSize).Offset(offset).Find(&items).Error; err != nil {
+	if err := db.Where(query, args...).Limit(pageSize).Offset(offset).Find(&items).Error; err != nil {
 		return nil, err
 	}
 
@@ -154,6 +235,104 @@ func (r *Repository[T, ID]) Pageable(ctx context.Context, page int, pageSize int
 	}, nil
 }
 
+// Find tìm danh sách entity theo Spec
+func (r *Repository[T, ID]) Find(ctx context.Context, spec *Spec) ([]T, error) {
+	db, err := r.withSpec(r.Reader(ctx), spec)
+	if err != nil {
+		return nil, err
+	}
+	var list []T
+	err = db.Find(&list).Error
+	return list, err
+}
+
+// FindOne tìm một entity theo Spec, trả về gorm.ErrRecordNotFound nếu không có
+func (r *Repository[T, ID]) FindOne(ctx context.Context, spec *Spec) (*T, error) {
+	db, err := r.withSpec(r.Reader(ctx), spec)
+	if err != nil {
+		return nil, err
+	}
+	var item T
+	if err := db.First(&item).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// Page phân trang theo Spec
+func (r *Repository[T, ID]) Page(ctx context.Context, spec *Spec, page int, pageSize int) (*Page[T], error) {
+	sch, err := r.schema()
+	if err != nil {
+		return nil, err
+	}
+	if page < 1 {
+		page = 1
+	}
+	// Dùng cùng một kết nối đọc cho cả count và dữ liệu
+	reader := r.Reader(ctx)
+
+	countDB, err := spec.applyFilter(reader.Model(new(T)), sch)
+	if err != nil {
+		return nil, err
+	}
+	var total int64
+	if err := countDB.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	db, err := spec.apply(reader.Model(new(T)), sch)
+	if err != nil {
+		return nil, err
+	}
+	var items []T
+	if err := db.Limit(pageSize).Offset((page - 1) * pageSize).Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	return &Page[T]{
+		Items:      items,
+		TotalCount: total,
+		Page:       page,
+		PageSize:   pageSize,
+		Sort:       spec.Orders(),
+	}, nil
+}
+
+func (r *Repository[T, ID]) withSpec(db *gorm.DB, spec *Spec) (*gorm.DB, error) {
+	sch, err := r.schema()
+	if err != nil {
+		return nil, err
+	}
+	return spec.apply(db.Model(new(T)), sch)
+}
+
+// primaryKeyEq tạo điều kiện khóa chính = id. id luôn là tham số bind, không dùng inline condition
+// của GORM vì ID kiểu chuỗi như "1=1" sẽ bị hiểu là SQL
+func (r *Repository[T, ID]) primaryKeyEq(id ID) (clause.Expression, error) {
+	sch, err := r.schema()
+	if err != nil {
+		return nil, err
+	}
+	if sch.PrioritizedPrimaryField == nil {
+		return nil, fmt.Errorf("gormx: %s has no primary key", sch.Name)
+	}
+	return clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: sch.PrioritizedPrimaryField.DBName}, Value: id}, nil
+}
+
+// Schema trả về schema GORM của T theo NamingStrategy của DataSource
+func (r *Repository[T, ID]) Schema() (*schema.Schema, error) {
+	return r.schema()
+}
+
+// schema trả về schema GORM của T (được GORM cache)
+func (r *Repository[T, ID]) schema() (*schema.Schema, error) {
+	stmt := &gorm.Statement{DB: r.DB}
+	if err := stmt.Parse(new(T)); err != nil {
+		return nil, err
+	}
+	return stmt.Schema, nil
+}
+
 // tableName trả về tên bảng của entity
 func (r *Repository[T, ID]) tableName() string {
 	entity := new(T)
@@ -0,0 +1,43 @@
+package gormx_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.io/xhkzeroone/goframex/pkg/database/gormx"
+	"github.io/xhkzeroone/goframex/pkg/database/gormx/gormxtest"
+	"gorm.io/gorm"
+)
+
+type tag struct {
+	Code  string `gorm:"primaryKey;size:32"`
+	Label string
+}
+
+func TestStringIDIsBoundAsValue(t *testing.T) {
+	ds := gormxtest.New(t, gormxtest.WithModels(&tag{}))
+	ctx := gormxtest.Begin(t, ds)
+	repo := gormx.NewRepository[tag, string](ds)
+	if err := repo.InsertBatch(ctx, []tag{{Code: "a", Label: "A"}, {Code: "b", Label: "B"}}, 0); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	// ID từ path (ví dụ DELETE /tags/1=1) không được hiểu là SQL
+	for _, id := range []string{"1=1", "code <> ''", "1"} {
+		if _, err := repo.FindByID(ctx, id); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("find %q err = %v, want not found", id, err)
+		}
+		if err := repo.DeleteByID(ctx, id); err != nil {
+			t.Errorf("delete %q: %v", id, err)
+		}
+	}
+	gormxtest.AssertRowCount(t, ctx, ds, &tag{}, 2)
+
+	if got, err := repo.FindByID(ctx, "b"); err != nil || got.Label != "B" {
+		t.Fatalf("find b = %+v, %v", got, err)
+	}
+	if err := repo.DeleteByID(ctx, "a"); err != nil {
+		t.Fatalf("delete a: %v", err)
+	}
+	gormxtest.AssertNotExists(t, ctx, ds, &tag{}, "code = ?", "a")
+}
@@ -0,0 +1,82 @@
+package gormx
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type routingKey int
+
+const (
+	primaryKey routingKey = iota
+	sessionKey
+)
+
+// session ghi nhận thời điểm ghi gần nhất để đảm bảo read-your-writes
+type session struct {
+	mu        sync.Mutex
+	lastWrite time.Time
+}
+
+// UsePrimary buộc mọi truy vấn đọc trong ctx đi vào primary
+func UsePrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey, true)
+}
+
+// WithReadYourWrites gắn session vào ctx: sau khi ghi, các lần đọc trong cùng ctx
+// sẽ đi vào primary trong khoảng Config.ReadAfterWriteWindow.
+// Thường được gắn một lần cho mỗi request bởi middleware
+func WithReadYourWrites(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(sessionKey).(*session); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, sessionKey, &session{})
+}
+
+func markWrite(ctx context.Context) {
+	if s, ok := ctx.Value(sessionKey).(*session); ok {
+		s.mu.Lock()
+		s.lastWrite = time.Now()
+		s.mu.Unlock()
+	}
+}
+
+func (p *DataSource) pinnedToPrimary(ctx context.Context) bool {
+	if pinned, _ := ctx.Value(primaryKey).(bool); pinned {
+		return true
+	}
+	if s, ok := ctx.Value(sessionKey).(*session); ok && p.Config.ReadAfterWriteWindow > 0 {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return !s.lastWrite.IsZero() && time.Since(s.lastWrite) < p.Config.ReadAfterWriteWindow
+	}
+	return false
+}
+
+// Reader trả về *gorm.DB cho truy vấn đọc: transaction trong ctx nếu có, replica nếu có, ngược lại là primary
+func (p *DataSource) Reader(ctx context.Context) *gorm.DB {
+	if tx, ok := p.txFromContext(ctx); ok {
+		return tx
+	}
+	if p.replicas == nil || p.pinnedToPrimary(ctx) {
+		return p.DB.WithContext(ctx)
+	}
+	if r := p.replicas.pick(); r != nil {
+		if db := r.db.Load(); db != nil {
+			return db.WithContext(ctx)
+		}
+	}
+	return p.DB.WithContext(ctx)
+}
+
+// Writer trả về *gorm.DB cho truy vấn ghi (transaction trong ctx hoặc primary) và đánh dấu session đã ghi
+func (p *DataSource) Writer(ctx context.Context) *gorm.DB {
+	markWrite(ctx)
+	if tx, ok := p.txFromContext(ctx); ok {
+		return tx
+	}
+	return p.DB.WithContext(ctx)
+}
@@ -0,0 +1,337 @@
+package gormx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// ErrUnknownColumn trả về khi Spec tham chiếu tới cột/quan hệ không có trong schema của entity
+var ErrUnknownColumn = errors.New("gormx: unknown column")
+
+// Condition là một điều kiện lọc, tên cột được kiểm tra theo schema GORM của entity khi Build.
+// Có thể tự cài đặt Condition để bổ sung toán tử riêng của từng database
+type Condition interface {
+	Build(sch *schema.Schema) (clause.Expression, error)
+}
+
+// ConditionFunc cho phép dùng function làm Condition
+type ConditionFunc func(sch *schema.Schema) (clause.Expression, error)
+
+func (f ConditionFunc) Build(sch *schema.Schema) (clause.Expression, error) {
+	return f(sch)
+}
+
+// Order mô tả một cột sắp xếp
+type Order struct {
+	Column string `json:"column"`
+	Desc   bool   `json:"desc"`
+}
+
+func Asc(column string) Order  { return Order{Column: column} }
+func Desc(column string) Order { return Order{Column: column, Desc: true} }
+
+// OrderExpression là biểu thức sắp xếp (ví dụ RankBy), được build theo schema của entity
+type OrderExpression interface {
+	BuildOrder(sch *schema.Schema) (clause.Expression, error)
+}
+
+// OrderExpressionFunc cho phép dùng function làm OrderExpression
+type OrderExpressionFunc func(sch *schema.Schema) (clause.Expression, error)
+
+func (f OrderExpressionFunc) BuildOrder(sch *schema.Schema) (clause.Expression, error) {
+	return f(sch)
+}
+
+// Spec là bộ truy vấn có kiểu dùng cho Repository.Find, FindOne, Count, Page
+type Spec struct {
+	conds      []Condition
+	orders     []Order
+	exprOrders []OrderExpression
+	preloads   []string
+	selects    []string
+	limit      int
+}
+
+// NewSpec tạo Spec với các điều kiện nối bằng AND
+func NewSpec(conds ...Condition) *Spec {
+	return &Spec{conds: conds}
+}
+
+// Where thêm điều kiện (AND)
+func (s *Spec) Where(conds ...Condition) *Spec {
+	s.conds = append(s.conds, conds...)
+	return s
+}
+
+func (s *Spec) OrderBy(orders ...Order) *Spec {
+	s.orders = append(s.orders, orders...)
+	return s
+}
+
+// OrderByExpr sắp xếp theo biểu thức, luôn đứng trước các cột của OrderBy. Không dùng được với Cursor
+func (s *Spec) OrderByExpr(exprs ...OrderExpression) *Spec {
+	s.exprOrders = append(s.exprOrders, exprs...)
+	return s
+}
+
+// Preload nạp quan hệ (tên field quan hệ của entity, cho phép lồng "Orders.Items")
+func (s *Spec) Preload(relations ...string) *Spec {
+	s.preloads = append(s.preloads, relations...)
+	return s
+}
+
+// Select giới hạn các cột được lấy
+func (s *Spec) Select(columns ...string) *Spec {
+	s.selects = append(s.selects, columns...)
+	return s
+}
+
+func (s *Spec) Limit(limit int) *Spec {
+	s.limit = limit
+	return s
+}
+
+// HasFilter cho biết Spec có điều kiện lọc hay không, nhóm And/Or/Not rỗng (kể cả lồng nhau) không được tính
+func (s *Spec) HasFilter() bool {
+	if s == nil {
+		return false
+	}
+	for _, c := range s.conds {
+		if g, ok := c.(group); !ok || !g.empty() {
+			return true
+		}
+	}
+	return false
+}
+
+// Orders trả về các cột sắp xếp đã khai báo
+func (s *Spec) Orders() []Order {
+	if s == nil {
+		return nil
+	}
+	return s.orders
+}
+
+// applyFilter chỉ áp dụng điều kiện WHERE (dùng cho Count)
+func (s *Spec) applyFilter(db *gorm.DB, sch *schema.Schema) (*gorm.DB, error) {
+	if s == nil {
+		return db, nil
+	}
+	exprs, err := buildConditions(s.conds, sch)
+	if err != nil || len(exprs) == 0 {
+		return db, err
+	}
+	return db.Clauses(clause.Where{Exprs: exprs}), nil
+}
+
+// buildConditions build các điều kiện, bỏ qua điều kiện build ra nil (ví dụ nhóm rỗng)
+func buildConditions(conds []Condition, sch *schema.Schema) ([]clause.Expression, error) {
+	exprs := make([]clause.Expression, 0, len(conds))
+	for _, c := range conds {
+		expr, err := c.Build(sch)
+		if err != nil {
+			return nil, err
+		}
+		if expr != nil {
+			exprs = append(exprs, expr)
+		}
+	}
+	return exprs, nil
+}
+
+// apply áp dụng toàn bộ Spec: điều kiện, sắp xếp, select, preload, limit
+func (s *Spec) apply(db *gorm.DB, sch *schema.Schema) (*gorm.DB, error) {
+	db, err := s.applyFilter(db, sch)
+	if err != nil || s == nil {
+		return db, err
+	}
+
+	if len(s.exprOrders) > 0 {
+		if db, err = s.applyExprOrders(db, sch); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, o := range s.orders {
+			col, err := ResolveColumn(sch, o.Column)
+			if err != nil {
+				return nil, err
+			}
+			db = db.Order(clause.OrderByColumn{Column: col, Desc: o.Desc})
+		}
+	}
+
+	if len(s.selects) > 0 {
+		cols := make([]string, 0, len(s.selects))
+		for _, name := range s.selects {
+			col, err := ResolveColumn(sch, name)
+			if err != nil {
+				return nil, err
+			}
+			cols = append(cols, col.Name)
+		}
+		db = db.Select(cols)
+	}
+
+	for _, rel := range s.preloads {
+		root, _, _ := strings.Cut(rel, ".")
+		if _, ok := sch.Relationships.Relations[root]; !ok {
+			return nil, fmt.Errorf("%w: relation %s of %s", ErrUnknownColumn, rel, sch.Name)
+		}
+		db = db.Preload(rel)
+	}
+
+	if s.limit > 0 {
+		db = db.Limit(s.limit)
+	}
+	return db, nil
+}
+
+// applyExprOrders ghép biểu thức sắp xếp và các cột của OrderBy thành một mệnh đề ORDER BY
+func (s *Spec) applyExprOrders(db *gorm.DB, sch *schema.Schema) (*gorm.DB, error) {
+	exprs := make([]clause.Expression, 0, len(s.exprOrders)+len(s.orders))
+	for _, o := range s.exprOrders {
+		expr, err := o.BuildOrder(sch)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+	for _, o := range s.orders {
+		col, err := ResolveColumn(sch, o.Column)
+		if err != nil {
+			return nil, err
+		}
+		sql := "?"
+		if o.Desc {
+			sql = "? DESC"
+		}
+		exprs = append(exprs, clause.Expr{SQL: sql, Vars: []any{col}})
+	}
+	return db.Clauses(clause.OrderBy{Expression: clause.CommaExpression{Exprs: exprs}}), nil
+}
+
+// ResolveColumn tìm cột theo tên cột DB hoặc tên field Go
+func ResolveColumn(sch *schema.Schema, name string) (clause.Column, error) {
+	if f := sch.LookUpField(name); f != nil && f.DBName != "" {
+		return clause.Column{Table: clause.CurrentTable, Name: f.DBName}, nil
+	}
+	return clause.Column{}, fmt.Errorf("%w: %s of %s", ErrUnknownColumn, name, sch.Name)
+}
+
+func columnCondition(column string, build func(col clause.Column) clause.Expression) Condition {
+	return ConditionFunc(func(sch *schema.Schema) (clause.Expression, error) {
+		col, err := ResolveColumn(sch, column)
+		if err != nil {
+			return nil, err
+		}
+		return build(col), nil
+	})
+}
+
+func Eq(column string, value any) Condition {
+	return columnCondition(column, func(col clause.Column) clause.Expression {
+		return clause.Eq{Column: col, Value: value}
+	})
+}
+
+func Ne(column string, value any) Condition {
+	return columnCondition(column, func(col clause.Column) clause.Expression {
+		return clause.Neq{Column: col, Value: value}
+	})
+}
+
+func Gt(column string, value any) Condition {
+	return columnCondition(column, func(col clause.Column) clause.Expression {
+		return clause.Gt{Column: col, Value: value}
+	})
+}
+
+func Gte(column string, value any) Condition {
+	return columnCondition(column, func(col clause.Column) clause.Expression {
+		return clause.Gte{Column: col, Value: value}
+	})
+}
+
+func Lt(column string, value any) Condition {
+	return columnCondition(column, func(col clause.Column) clause.Expression {
+		return clause.Lt{Column: col, Value: value}
+	})
+}
+
+func Lte(column string, value any) Condition {
+	return columnCondition(column, func(col clause.Column) clause.Expression {
+		return clause.Lte{Column: col, Value: value}
+	})
+}
+
+func IsNull(column string) Condition {
+	return columnCondition(column, func(col clause.Column) clause.Expression {
+		return clause.Eq{Column: col, Value: nil}
+	})
+}
+
+// In kiểm tra cột thuộc danh sách giá trị
+func In[V any](column string, values ...V) Condition {
+	vals := make([]any, len(values))
+	for i, v := range values {
+		vals[i] = v
+	}
+	return columnCondition(column, func(col clause.Column) clause.Expression {
+		return clause.IN{Column: col, Values: vals}
+	})
+}
+
+// Like so khớp mẫu, pattern do người gọi truyền vào (ví dụ "%abc%")
+func Like(column string, pattern string) Condition {
+	return columnCondition(column, func(col clause.Column) clause.Expression {
+		return clause.Like{Column: col, Value: pattern}
+	})
+}
+
+func Between(column string, from, to any) Condition {
+	return columnCondition(column, func(col clause.Column) clause.Expression {
+		return clause.Expr{SQL: "? BETWEEN ? AND ?", Vars: []any{col, from, to}}
+	})
+}
+
+// And, Or, Not gộp các điều kiện thành một nhóm. Nhóm rỗng (hoặc chỉ chứa nhóm rỗng) build ra nil
+// và bị bỏ qua, không được tính là điều kiện lọc
+func And(conds ...Condition) Condition {
+	return group{conds: conds, combine: clause.And}
+}
+
+func Or(conds ...Condition) Condition {
+	return group{conds: conds, combine: clause.Or}
+}
+
+func Not(conds ...Condition) Condition {
+	return group{conds: conds, combine: clause.Not}
+}
+
+type group struct {
+	conds   []Condition
+	combine func(exprs ...clause.Expression) clause.Expression
+}
+
+func (g group) Build(sch *schema.Schema) (clause.Expression, error) {
+	exprs, err := buildConditions(g.conds, sch)
+	if err != nil || len(exprs) == 0 {
+		return nil, err
+	}
+	return g.combine(exprs...), nil
+}
+
+// empty cho biết nhóm không có điều kiện nào ngoài các nhóm rỗng
+func (g group) empty() bool {
+	for _, c := range g.conds {
+		if inner, ok := c.(group); !ok || !inner.empty() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,156 @@
+package gormx_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.io/xhkzeroone/goframex/pkg/database/gormx"
+	"github.io/xhkzeroone/goframex/pkg/database/gormx/gormxtest"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+type product struct {
+	ID       uint   `gorm:"primaryKey"`
+	Name     string `gorm:"size:64;not null;uniqueIndex"`
+	Category string `gorm:"size:32;not null"`
+	Price    int    `gorm:"not null"`
+}
+
+// seedProducts tạo repository product với dữ liệu mẫu trong transaction của test
+func seedProducts(t *testing.T) (context.Context, *gormx.Repository[product, uint]) {
+	t.Helper()
+	ds := gormxtest.New(t, gormxtest.WithModels(&product{}))
+	ctx := gormxtest.Begin(t, ds)
+	repo := gormx.NewRepository[product, uint](ds)
+	items := []product{
+		{ID: 1, Name: "apple", Category: "fruit", Price: 30},
+		{ID: 2, Name: "banana", Category: "fruit", Price: 10},
+		{ID: 3, Name: "carrot", Category: "vegetable", Price: 20},
+		{ID: 4, Name: "durian", Category: "fruit", Price: 90},
+		{ID: 5, Name: "eggplant", Category: "vegetable", Price: 20},
+	}
+	if err := repo.InsertBatch(ctx, items, 2); err != nil {
+		t.Fatalf("insert batch: %v", err)
+	}
+	return ctx, repo
+}
+
+func names(items []product) []string {
+	out := make([]string, len(items))
+	for i, p := range items {
+		out[i] = p.Name
+	}
+	return out
+}
+
+func TestSpecFind(t *testing.T) {
+	ctx, repo := seedProducts(t)
+
+	tests := []struct {
+		name string
+		spec *gormx.Spec
+		want []string
+	}{
+		{"eq", gormx.NewSpec(gormx.Eq("category", "vegetable")).OrderBy(gormx.Asc("name")), []string{"carrot", "eggplant"}},
+		{"field name", gormx.NewSpec(gormx.Gte("Price", 30)).OrderBy(gormx.Desc("Price")), []string{"durian", "apple"}},
+		{"in", gormx.NewSpec(gormx.In("id", 1, 3)).OrderBy(gormx.Asc("id")), []string{"apple", "carrot"}},
+		{"or", gormx.NewSpec(gormx.Or(gormx.Lt("price", 15), gormx.Gt("price", 50))).OrderBy(gormx.Asc("price")), []string{"banana", "durian"}},
+		{"not", gormx.NewSpec(gormx.Not(gormx.Eq("category", "fruit"))).OrderBy(gormx.Asc("name")), []string{"carrot", "eggplant"}},
+		{"like", gormx.NewSpec(gormx.Like("name", "%an%")).OrderBy(gormx.Asc("name")), []string{"banana", "durian", "eggplant"}},
+		{"between", gormx.NewSpec(gormx.Between("price", 15, 30)).OrderBy(gormx.Desc("price"), gormx.Asc("name")), []string{"apple", "carrot", "eggplant"}},
+		{"empty group ignored", gormx.NewSpec(gormx.Eq("category", "vegetable"), gormx.And(), gormx.Or(gormx.Not())).OrderBy(gormx.Asc("name")), []string{"carrot", "eggplant"}},
+		{"nested empty group dropped", gormx.NewSpec(gormx.Or(gormx.And(), gormx.Eq("name", "apple"))), []string{"apple"}},
+		{"only empty groups", gormx.NewSpec(gormx.And(gormx.Or())).OrderBy(gormx.Asc("id")).Limit(1), []string{"apple"}},
+		{"limit", gormx.NewSpec().OrderBy(gormx.Desc("price"), gormx.Asc("id")).Limit(2), []string{"durian", "apple"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := repo.Find(ctx, tt.spec)
+			if err != nil {
+				t.Fatalf("find: %v", err)
+			}
+			if got := names(items); !slices.Equal(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpecRejectsUnknownColumn(t *testing.T) {
+	ctx, repo := seedProducts(t)
+
+	specs := map[string]*gormx.Spec{
+		"condition": gormx.NewSpec(gormx.Eq("price; DROP TABLE products", 1)),
+		"order":     gormx.NewSpec().OrderBy(gormx.Asc("missing")),
+		"select":    gormx.NewSpec().Select("missing"),
+	}
+	for name, spec := range specs {
+		t.Run(name, func(t *testing.T) {
+			if _, err := repo.Find(ctx, spec); !errors.Is(err, gormx.ErrUnknownColumn) {
+				t.Fatalf("err = %v, want ErrUnknownColumn", err)
+			}
+		})
+	}
+}
+
+func TestSpecCountAndPage(t *testing.T) {
+	ctx, repo := seedProducts(t)
+	spec := gormx.NewSpec(gormx.Eq("category", "fruit")).OrderBy(gormx.Asc("price"))
+
+	n, err := repo.Count(ctx, spec)
+	if err != nil || n != 3 {
+		t.Fatalf("count = %d, %v; want 3", n, err)
+	}
+
+	page, err := repo.Page(ctx, spec, 2, 2)
+	if err != nil {
+		t.Fatalf("page: %v", err)
+	}
+	if page.TotalCount != 3 || !slices.Equal(names(page.Items), []string{"durian"}) {
+		t.Fatalf("page = %+v", page)
+	}
+
+	item, err := repo.FindOne(ctx, spec)
+	if err != nil || item.Name != "banana" {
+		t.Fatalf("find one = %+v, %v", item, err)
+	}
+}
+
+func TestSpecEmptyGroupsAreNotFilters(t *testing.T) {
+	ctx, repo := seedProducts(t)
+
+	for name, spec := range map[string]*gormx.Spec{
+		"nil":          nil,
+		"no condition": gormx.NewSpec(),
+		"and":          gormx.NewSpec(gormx.And()),
+		"or":           gormx.NewSpec(gormx.Or()),
+		"not":          gormx.NewSpec(gormx.Not()),
+		"nested":       gormx.NewSpec(gormx.And(gormx.Or(), gormx.Not(gormx.And()))),
+		"custom nil": gormx.NewSpec(gormx.ConditionFunc(func(*schema.Schema) (clause.Expression, error) {
+			return nil, nil
+		})),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if name != "custom nil" && spec.HasFilter() {
+				t.Error("HasFilter() = true")
+			}
+			if n, err := repo.Count(ctx, spec); err != nil || n != 5 {
+				t.Errorf("count = %d, %v; want 5", n, err)
+			}
+			if _, err := repo.DeleteWhere(ctx, spec); !errors.Is(err, gormx.ErrEmptyFilter) {
+				t.Errorf("delete err = %v, want ErrEmptyFilter", err)
+			}
+			if _, err := repo.UpdateWhere(ctx, spec, map[string]any{"price": 0}); !errors.Is(err, gormx.ErrEmptyFilter) {
+				t.Errorf("update err = %v, want ErrEmptyFilter", err)
+			}
+		})
+	}
+	gormxtest.AssertRowCount(t, ctx, repo.DataSource, &product{}, 5)
+
+	if !gormx.NewSpec(gormx.And(gormx.Or(), gormx.Eq("id", 1))).HasFilter() {
+		t.Error("HasFilter() = false for a group with a condition")
+	}
+}
@@ -0,0 +1,297 @@
+package gormx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+var (
+	// ErrMissingTenant trả về khi truy vấn cần tenant nhưng context không có tenant
+	ErrMissingTenant = errors.New("gormx: tenant is required but missing from context")
+	// ErrTenantMismatch trả về khi ghi entity mang tenant_id khác tenant trong context
+	ErrTenantMismatch = errors.New("gormx: entity belongs to another tenant")
+	// ErrInvalidTenant trả về khi tenant id không dùng được làm tên schema
+	ErrInvalidTenant = errors.New("gormx: invalid tenant identifier")
+)
+
+// TenantResolver lấy tenant id từ context
+type TenantResolver interface {
+	ResolveTenant(ctx context.Context) (string, error)
+}
+
+// TenantResolverFunc cho phép dùng function làm TenantResolver
+type TenantResolverFunc func(ctx context.Context) (string, error)
+
+func (f TenantResolverFunc) ResolveTenant(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// ContextTenantResolver đọc tenant đã gắn bằng WithTenant, là resolver mặc định
+var ContextTenantResolver TenantResolver = TenantResolverFunc(func(ctx context.Context) (string, error) {
+	if tenant, ok := TenantFromContext(ctx); ok {
+		return tenant, nil
+	}
+	return "", ErrMissingTenant
+})
+
+type tenantKey struct{}
+type withoutTenantKey struct{}
+
+// WithTenant gắn tenant id vào ctx, thường được gắn một lần cho mỗi request bởi middleware
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantFromContext trả về tenant đã gắn bằng WithTenant
+func TenantFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	tenant, ok := ctx.Value(tenantKey{}).(string)
+	return tenant, ok && tenant != ""
+}
+
+// WithoutTenant tắt việc lọc theo tenant và guard cho các truy vấn dùng ctx (job quản trị, migration...)
+func WithoutTenant(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withoutTenantKey{}, true)
+}
+
+func tenantBypassed(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	bypass, _ := ctx.Value(withoutTenantKey{}).(bool)
+	return bypass
+}
+
+// TenantScoped nhúng vào entity để dùng chiến lược shared table: mọi truy vấn/ghi của entity
+// tự động lọc và gán tenant_id theo tenant trong context
+type TenantScoped struct {
+	TenantID string `gorm:"column:tenant_id;size:64;not null;index"`
+}
+
+type tenantScoped interface{ tenant() *TenantScoped }
+
+func (t *TenantScoped) tenant() *TenantScoped { return t }
+
+var tenantScopedType = reflect.TypeOf((*tenantScoped)(nil)).Elem()
+
+// WithTenantResolver đổi cách lấy tenant từ context (mặc định ContextTenantResolver)
+func WithTenantResolver(r TenantResolver) Option {
+	return func(o *options) {
+		o.tenancy.resolver = r
+	}
+}
+
+// WithSchemaPerTenant bật chiến lược schema-per-tenant: bảng của mọi truy vấn được gắn schema
+// của tenant, Transactional còn đặt search_path (Postgres) để raw SQL/join cũng dùng đúng schema.
+// schemaName nil sẽ dùng "tenant_<id>"
+func WithSchemaPerTenant(schemaName func(tenantID string) string) Option {
+	return func(o *options) {
+		if schemaName == nil {
+			schemaName = func(tenantID string) string { return "tenant_" + tenantID }
+		}
+		o.tenancy.schemaName = schemaName
+	}
+}
+
+type tenancy struct {
+	resolver   TenantResolver
+	schemaName func(tenantID string) string
+}
+
+var schemaNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// tenantSchema trả về schema của tenant trong ctx, rỗng nếu không bật schema-per-tenant hoặc đang bypass
+func (t *tenancy) tenantSchema(ctx context.Context) (string, error) {
+	if t == nil || t.schemaName == nil || tenantBypassed(ctx) {
+		return "", nil
+	}
+	tenant, err := t.resolve(ctx)
+	if err != nil {
+		return "", err
+	}
+	name := t.schemaName(tenant)
+	if !schemaNamePattern.MatchString(name) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidTenant, name)
+	}
+	return name, nil
+}
+
+// TenantSchema trả về schema của tenant trong ctx khi bật WithSchemaPerTenant,
+// rỗng nếu không bật hoặc ctx đã WithoutTenant
+func (p *DataSource) TenantSchema(ctx context.Context) (string, error) {
+	return p.tenancy.tenantSchema(ctx)
+}
+
+func (t *tenancy) resolve(ctx context.Context) (string, error) {
+	resolver := ContextTenantResolver
+	if t != nil && t.resolver != nil {
+		resolver = t.resolver
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	tenant, err := resolver.ResolveTenant(ctx)
+	if err == nil && tenant == "" {
+		err = ErrMissingTenant
+	}
+	return tenant, err
+}
+
+// registerTenantCallbacks đăng ký callback lọc/gán tenant cho entity TenantScoped và gắn schema theo tenant
+func registerTenantCallbacks(db *gorm.DB, t *tenancy) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("gormx:tenant_create", t.beforeCreate),
+		cb.Query().Before("gorm:query").Register("gormx:tenant_query", t.beforeQuery),
+		cb.Row().Before("gorm:row").Register("gormx:tenant_row", t.beforeQuery),
+		cb.Update().Before("gorm:update").Register("gormx:tenant_update", t.beforeUpdate),
+		cb.Delete().Before("gorm:delete").Register("gormx:tenant_delete", t.beforeWrite),
+	)
+}
+
+func (t *tenancy) beforeCreate(db *gorm.DB) {
+	if !t.prepare(db) || !isTenantScoped(db) {
+		return
+	}
+	tenant, err := t.resolve(db.Statement.Context)
+	if err != nil {
+		_ = db.AddError(err)
+		return
+	}
+	assignTenant(db, db.Statement.ReflectValue, tenant)
+}
+
+// assignTenant gán tenant cho entity chưa có tenant_id, báo ErrTenantMismatch nếu entity thuộc tenant khác
+func assignTenant(db *gorm.DB, rv reflect.Value, tenant string) {
+	eachModel(rv, func(model any) {
+		s, ok := model.(tenantScoped)
+		if !ok {
+			return
+		}
+		switch m := s.tenant(); m.TenantID {
+		case "":
+			m.TenantID = tenant
+		case tenant:
+		default:
+			_ = db.AddError(ErrTenantMismatch)
+		}
+	})
+}
+
+func (t *tenancy) beforeQuery(db *gorm.DB) {
+	if !t.prepare(db) || !isTenantScoped(db) {
+		return
+	}
+	t.addTenantCondition(db)
+}
+
+func (t *tenancy) beforeWrite(db *gorm.DB) {
+	t.guardWrite(db)
+}
+
+// beforeUpdate như beforeWrite nhưng còn chặn việc chuyển dòng sang tenant khác qua giá trị SET
+func (t *tenancy) beforeUpdate(db *gorm.DB) {
+	if tenant, ok := t.guardWrite(db); ok {
+		checkTenantValues(db, tenant)
+	}
+}
+
+// guardWrite chặn update/delete không điều kiện và thêm điều kiện tenant, trả về tenant khi đã lọc
+func (t *tenancy) guardWrite(db *gorm.DB) (string, bool) {
+	if !t.prepare(db) || !isTenantScoped(db) {
+		return "", false
+	}
+	// Điều kiện tenant không được tính là điều kiện WHERE, vẫn chặn update/delete toàn bảng như GORM
+	if !db.AllowGlobalUpdate && !hasWriteCondition(db) {
+		_ = db.AddError(gorm.ErrMissingWhereClause)
+		return "", false
+	}
+	return t.addTenantCondition(db)
+}
+
+// checkTenantValues báo ErrTenantMismatch nếu Dest (struct hoặc map) gán tenant_id khác tenant,
+// struct chưa có tenant_id (Save ghi mọi cột) được gán tenant hiện tại
+func checkTenantValues(db *gorm.DB, tenant string) {
+	stmt := db.Statement
+	switch dest := stmt.Dest.(type) {
+	case map[string]any:
+		for key, value := range dest {
+			field := stmt.Schema.LookUpField(key)
+			if field == nil || field.DBName != "tenant_id" {
+				continue
+			}
+			if s, ok := value.(string); !ok || s != tenant {
+				_ = db.AddError(ErrTenantMismatch)
+				return
+			}
+		}
+	default:
+		assignTenant(db, reflect.ValueOf(stmt.Dest), tenant)
+	}
+}
+
+// prepare kiểm tra guard và gắn schema của tenant, trả về false nếu không cần xử lý tiếp
+func (t *tenancy) prepare(db *gorm.DB) bool {
+	stmt := db.Statement
+	// Raw SQL đã dựng sẵn, không thể lọc tự động
+	if db.Error != nil || stmt.Schema == nil || stmt.SQL.Len() > 0 || tenantBypassed(stmt.Context) {
+		return false
+	}
+
+	schemaName, err := t.tenantSchema(stmt.Context)
+	if err != nil {
+		_ = db.AddError(err)
+		return false
+	}
+	if schemaName != "" && stmt.TableExpr == nil && stmt.Table != "" && !strings.Contains(stmt.Table, ".") {
+		stmt.Table = schemaName + "." + stmt.Table
+	}
+	return true
+}
+
+func (t *tenancy) addTenantCondition(db *gorm.DB) (string, bool) {
+	tenant, err := t.resolve(db.Statement.Context)
+	if err != nil {
+		_ = db.AddError(err)
+		return "", false
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: "tenant_id"}, Value: tenant},
+	}})
+	return tenant, true
+}
+
+func isTenantScoped(db *gorm.DB) bool {
+	return reflect.PointerTo(db.Statement.Schema.ModelType).Implements(tenantScopedType)
+}
+
+// hasWriteCondition cho biết update/delete có điều kiện WHERE hoặc khóa chính hay không
+func hasWriteCondition(db *gorm.DB) bool {
+	stmt := db.Statement
+	if _, ok := stmt.Clauses["WHERE"]; ok {
+		return true
+	}
+	if _, values := schema.GetIdentityFieldValuesMap(stmt.Context, stmt.ReflectValue, stmt.Schema.PrimaryFields); len(values) > 0 {
+		return true
+	}
+	// Model(new(T)).Save(entity): GORM lấy khóa chính từ Dest
+	for _, v := range []any{stmt.Model, stmt.Dest} {
+		if v == nil {
+			continue
+		}
+		if _, values := schema.GetIdentityFieldValuesMap(stmt.Context, reflect.ValueOf(v), stmt.Schema.PrimaryFields); len(values) > 0 {
+			return true
+		}
+	}
+	return false
+}
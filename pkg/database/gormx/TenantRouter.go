@@ -0,0 +1,86 @@
+package gormx
+
+import (
+	"context"
+	"fmt"
+)
+
+// TenantRouter chọn DataSource theo tenant cho chiến lược database-per-tenant,
+// mỗi tenant là một instance trong Manager với tên "tenant:<id>"
+type TenantRouter struct {
+	manager   *Manager
+	resolver  TenantResolver
+	configFor func(tenantID string) (*Config, error)
+	dsOpts    []Option
+}
+
+type TenantRouterOption func(r *TenantRouter)
+
+// WithRouterResolver đổi cách lấy tenant từ context (mặc định ContextTenantResolver)
+func WithRouterResolver(resolver TenantResolver) TenantRouterOption {
+	return func(r *TenantRouter) {
+		r.resolver = resolver
+	}
+}
+
+// WithTenantConfig cho phép tự đăng ký database của tenant chưa có trong Manager ở lần dùng đầu tiên
+func WithTenantConfig(configFor func(tenantID string) (*Config, error), opts ...Option) TenantRouterOption {
+	return func(r *TenantRouter) {
+		r.configFor = configFor
+		r.dsOpts = opts
+	}
+}
+
+func NewTenantRouter(manager *Manager, opts ...TenantRouterOption) *TenantRouter {
+	r := &TenantRouter{manager: manager, resolver: ContextTenantResolver}
+	for _, o := range opts {
+		o(r)
+	}
+	return r
+}
+
+// TenantInstanceName trả về tên instance trong Manager của tenant
+func TenantInstanceName(tenantID string) string {
+	return "tenant:" + tenantID
+}
+
+// Register đăng ký sẵn database của tenant
+func (r *TenantRouter) Register(tenantID string, cfg *Config, opts ...Option) error {
+	return r.manager.Register(TenantInstanceName(tenantID), cfg, opts...)
+}
+
+// DataSource trả về DataSource của tenant trong ctx, trả về ErrMissingTenant nếu ctx không có tenant
+func (r *TenantRouter) DataSource(ctx context.Context) (*DataSource, error) {
+	tenant, err := r.resolver.ResolveTenant(ctx)
+	if err == nil && tenant == "" {
+		err = ErrMissingTenant
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	name := TenantInstanceName(tenant)
+	if !r.manager.exists(name) {
+		if r.configFor == nil {
+			return nil, fmt.Errorf("%w: no database registered for tenant %s", ErrInvalidTenant, tenant)
+		}
+		cfg, err := r.configFor(tenant)
+		if err != nil {
+			return nil, err
+		}
+		// Có thể request khác vừa đăng ký cùng tenant, khi đó chỉ cần Get
+		if err := r.manager.Register(name, cfg, r.dsOpts...); err != nil && !r.manager.exists(name) {
+			return nil, err
+		}
+	}
+	return r.manager.Get(name)
+}
+
+// TenantRepository tạo Repository trên database của tenant trong ctx
+func TenantRepository[T any, ID comparable](ctx context.Context, r *TenantRouter) (*Repository[T, ID], error) {
+	ds, err := r.DataSource(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return NewRepository[T, ID](ds), nil
+}
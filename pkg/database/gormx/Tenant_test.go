@@ -0,0 +1,157 @@
+package gormx_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.io/xhkzeroone/goframex/pkg/database/gormx"
+	"github.io/xhkzeroone/goframex/pkg/database/gormx/gormxtest"
+	"gorm.io/gorm"
+)
+
+type note struct {
+	ID   uint `gorm:"primaryKey"`
+	Body string
+	gormx.TenantScoped
+}
+
+func TestTenantScopedIsolation(t *testing.T) {
+	ds := gormxtest.New(t, gormxtest.WithModels(&note{}))
+	ctx := gormxtest.Begin(t, ds)
+	repo := gormx.NewRepository[note, uint](ds)
+	acme := gormx.WithTenant(ctx, "acme")
+	globex := gormx.WithTenant(ctx, "globex")
+
+	for _, n := range []struct {
+		ctx  context.Context
+		body string
+	}{{acme, "a1"}, {acme, "a2"}, {globex, "g1"}} {
+		if err := repo.Insert(n.ctx, &note{Body: n.body}); err != nil {
+			t.Fatalf("insert %s: %v", n.body, err)
+		}
+	}
+
+	// tenant_id được gán theo ctx và mọi truy vấn chỉ thấy dữ liệu của tenant đó
+	gormxtest.AssertRowCount(t, gormx.WithoutTenant(ctx), ds, &note{}, 2, "tenant_id = ?", "acme")
+	if n, err := repo.Count(acme); err != nil || n != 2 {
+		t.Fatalf("acme count = %d, %v; want 2", n, err)
+	}
+	items, err := repo.Find(globex, gormx.NewSpec())
+	if err != nil || len(items) != 1 || items[0].Body != "g1" {
+		t.Fatalf("globex find = %+v, %v", items, err)
+	}
+	if _, err := repo.FindByID(globex, 1); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("cross-tenant find err = %v, want not found", err)
+	}
+
+	// Ghi của tenant khác không chạm được vào dòng của acme
+	if n, err := repo.UpdateWhere(globex, gormx.NewSpec(gormx.Eq("body", "a1")), map[string]any{"body": "x"}); err != nil || n != 0 {
+		t.Fatalf("cross-tenant update = %d, %v; want 0", n, err)
+	}
+	if err := repo.DeleteByID(globex, 1); err != nil {
+		t.Fatalf("cross-tenant delete: %v", err)
+	}
+	gormxtest.AssertExists(t, acme, ds, &note{}, "body = ?", "a1")
+
+	if n, err := repo.Count(gormx.WithoutTenant(ctx)); err != nil || n != 3 {
+		t.Fatalf("bypass count = %d, %v; want 3", n, err)
+	}
+}
+
+func TestTenantGuards(t *testing.T) {
+	ds := gormxtest.New(t, gormxtest.WithModels(&note{}))
+	ctx := gormxtest.Begin(t, ds)
+	repo := gormx.NewRepository[note, uint](ds)
+
+	if _, err := repo.Find(ctx, gormx.NewSpec()); !errors.Is(err, gormx.ErrMissingTenant) {
+		t.Fatalf("find without tenant err = %v, want ErrMissingTenant", err)
+	}
+	if err := repo.Insert(ctx, &note{Body: "x"}); !errors.Is(err, gormx.ErrMissingTenant) {
+		t.Fatalf("insert without tenant err = %v, want ErrMissingTenant", err)
+	}
+
+	other := &note{Body: "x", TenantScoped: gormx.TenantScoped{TenantID: "globex"}}
+	if err := repo.Insert(gormx.WithTenant(ctx, "acme"), other); !errors.Is(err, gormx.ErrTenantMismatch) {
+		t.Fatalf("insert foreign entity err = %v, want ErrTenantMismatch", err)
+	}
+	gormxtest.AssertRowCount(t, gormx.WithoutTenant(ctx), ds, &note{}, 0)
+}
+
+func TestTenantResolver(t *testing.T) {
+	type headerKey struct{}
+	resolver := gormx.TenantResolverFunc(func(ctx context.Context) (string, error) {
+		if tenant, ok := ctx.Value(headerKey{}).(string); ok {
+			return tenant, nil
+		}
+		return "", gormx.ErrMissingTenant
+	})
+	ds := gormxtest.New(t,
+		gormxtest.WithModels(&note{}),
+		gormxtest.WithDataSourceOptions(gormx.WithTenantResolver(resolver)),
+	)
+	ctx := context.WithValue(gormxtest.Begin(t, ds), headerKey{}, "initech")
+	repo := gormx.NewRepository[note, uint](ds)
+
+	n := &note{Body: "x"}
+	if err := repo.Insert(ctx, n); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if n.TenantID != "initech" {
+		t.Fatalf("tenant = %q, want initech", n.TenantID)
+	}
+}
+
+func TestTenantUpdateCannotMoveRows(t *testing.T) {
+	ds := gormxtest.New(t, gormxtest.WithModels(&note{}))
+	ctx := gormxtest.Begin(t, ds)
+	repo := gormx.NewRepository[note, uint](ds)
+	acme := gormx.WithTenant(ctx, "acme")
+
+	n := &note{Body: "a1"}
+	if err := repo.Insert(acme, n); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	moved := *n
+	moved.TenantID = "globex"
+	if err := repo.Update(acme, &moved); !errors.Is(err, gormx.ErrTenantMismatch) {
+		t.Fatalf("update with changed tenant err = %v, want ErrTenantMismatch", err)
+	}
+	for _, values := range []map[string]any{{"tenant_id": "globex"}, {"TenantID": "globex"}, {"tenant_id": gorm.Expr("'globex'")}} {
+		if _, err := repo.UpdateWhere(acme, gormx.NewSpec(gormx.Eq("body", "a1")), values); !errors.Is(err, gormx.ErrTenantMismatch) {
+			t.Fatalf("UpdateWhere(%v) err = %v, want ErrTenantMismatch", values, err)
+		}
+	}
+	if err := ds.Writer(acme).Model(n).Update("tenant_id", "globex").Error; !errors.Is(err, gormx.ErrTenantMismatch) {
+		t.Fatalf("Update(tenant_id) err = %v, want ErrTenantMismatch", err)
+	}
+	gormxtest.AssertRowCount(t, gormx.WithoutTenant(ctx), ds, &note{}, 1, "tenant_id = ?", "acme")
+
+	// Giữ nguyên hoặc bỏ trống tenant_id vẫn cập nhật được, Save không xóa tenant_id
+	kept := &note{ID: n.ID, Body: "a2"}
+	if err := repo.Update(acme, kept); err != nil || kept.TenantID != "acme" {
+		t.Fatalf("update without tenant = %v, tenant %q", err, kept.TenantID)
+	}
+	if _, err := repo.UpdateWhere(acme, gormx.NewSpec(gormx.Eq("body", "a2")), map[string]any{"tenant_id": "acme", "body": "a3"}); err != nil {
+		t.Fatalf("UpdateWhere same tenant: %v", err)
+	}
+	gormxtest.AssertExists(t, acme, ds, &note{}, "body = ?", "a3")
+
+	// Update của tenant khác không khớp dòng nào và không được insert đè lên dòng của acme
+	if err := repo.Update(gormx.WithTenant(ctx, "globex"), &note{ID: n.ID, Body: "stolen"}); err != nil {
+		t.Fatalf("cross-tenant update: %v", err)
+	}
+	gormxtest.AssertRowCount(t, gormx.WithoutTenant(ctx), ds, &note{}, 1, "tenant_id = ? AND body = ?", "acme", "a3")
+
+	// Update qua Table() không có schema nên không được lọc theo tenant
+	if err := ds.Writer(acme).Table("notes").Where("id = ?", n.ID).Update("body", "raw").Error; err != nil {
+		t.Fatalf("table update: %v", err)
+	}
+
+	// WithoutTenant cho phép job quản trị chuyển dòng giữa các tenant
+	if err := ds.Writer(gormx.WithoutTenant(ctx)).Model(n).Update("tenant_id", "globex").Error; err != nil {
+		t.Fatalf("bypass update: %v", err)
+	}
+	gormxtest.AssertRowCount(t, gormx.WithoutTenant(ctx), ds, &note{}, 1, "tenant_id = ?", "globex")
+}
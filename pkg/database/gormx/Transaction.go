@@ -0,0 +1,137 @@
+package gormx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// txKey phân biệt transaction theo DataSource, cho phép lồng transaction của nhiều database
+type txKey struct {
+	ds *DataSource
+}
+
+// currentTxKey trỏ tới transaction trong cùng nhất, dùng cho AfterCommit
+type currentTxKey struct{}
+
+type txState struct {
+	db          *gorm.DB
+	parent      *txState
+	afterCommit []func(ctx context.Context)
+}
+
+type TxOption func(o *sql.TxOptions)
+
+// WithIsolation đặt isolation level cho transaction ngoài cùng
+func WithIsolation(level sql.IsolationLevel) TxOption {
+	return func(o *sql.TxOptions) {
+		o.Isolation = level
+	}
+}
+
+// WithReadOnly mở transaction chỉ đọc
+func WithReadOnly() TxOption {
+	return func(o *sql.TxOptions) {
+		o.ReadOnly = true
+	}
+}
+
+// Transactional chạy fn trong transaction và lưu tx vào ctx, mọi method của Repository
+// dùng ctx này sẽ tự động tham gia transaction.
+// Gọi lồng nhau trên cùng DataSource sẽ tạo savepoint (TxOption chỉ áp dụng cho transaction ngoài cùng).
+// fn trả về lỗi hoặc panic sẽ rollback
+func Transactional(ctx context.Context, ds *DataSource, fn func(ctx context.Context) error, opts ...TxOption) error {
+	parent, _ := ctx.Value(txKey{ds: ds}).(*txState)
+
+	base := ds.DB
+	if parent != nil {
+		base = parent.db
+	}
+
+	var txOpts []*sql.TxOptions
+	if len(opts) > 0 && parent == nil {
+		o := &sql.TxOptions{}
+		for _, opt := range opts {
+			opt(o)
+		}
+		txOpts = append(txOpts, o)
+	}
+
+	state := &txState{parent: parent}
+	err := base.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		state.db = tx
+		if parent == nil {
+			if err := ds.setTenantSearchPath(ctx, tx); err != nil {
+				return err
+			}
+		}
+		txCtx := context.WithValue(ctx, txKey{ds: ds}, state)
+		txCtx = context.WithValue(txCtx, currentTxKey{}, state)
+		return fn(txCtx)
+	}, txOpts...)
+	if err != nil {
+		return err
+	}
+
+	// Savepoint thành công: hook chỉ chạy khi transaction ngoài cùng commit
+	if parent != nil {
+		parent.afterCommit = append(parent.afterCommit, state.afterCommit...)
+		return nil
+	}
+	runAfterCommit(ctx, state.afterCommit)
+	return nil
+}
+
+// AfterCommit đăng ký hook chạy sau khi transaction ngoài cùng commit thành công
+// (ví dụ publish event). Nếu ctx không nằm trong transaction, hook chạy ngay
+func AfterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	state, ok := ctx.Value(currentTxKey{}).(*txState)
+	if !ok {
+		fn(ctx)
+		return
+	}
+	state.afterCommit = append(state.afterCommit, fn)
+}
+
+// InTransaction cho biết ctx có đang nằm trong transaction của ds hay không
+func InTransaction(ctx context.Context, ds *DataSource) bool {
+	_, ok := ctx.Value(txKey{ds: ds}).(*txState)
+	return ok
+}
+
+// setTenantSearchPath đặt search_path theo schema của tenant cho transaction (chỉ Postgres)
+func (p *DataSource) setTenantSearchPath(ctx context.Context, tx *gorm.DB) error {
+	if p.Dialector.Name() != DriverPostgres {
+		return nil
+	}
+	schemaName, err := p.tenancy.tenantSchema(ctx)
+	if err != nil || schemaName == "" {
+		return err
+	}
+	// schemaName đã được kiểm tra chỉ gồm ký tự an toàn
+	return tx.Exec(fmt.Sprintf(`SET LOCAL search_path TO "%s", public`, schemaName)).Error
+}
+
+func (p *DataSource) txFromContext(ctx context.Context) (*gorm.DB, bool) {
+	state, ok := ctx.Value(txKey{ds: p}).(*txState)
+	if !ok {
+		return nil, false
+	}
+	return state.db.WithContext(ctx), true
+}
+
+func runAfterCommit(ctx context.Context, hooks []func(ctx context.Context)) {
+	for _, hook := range hooks {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("gormx after-commit hook panic: %v", r)
+				}
+			}()
+			hook(ctx)
+		}()
+	}
+}
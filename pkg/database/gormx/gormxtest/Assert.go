@@ -0,0 +1,72 @@
+package gormxtest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.io/xhkzeroone/goframex/pkg/database/gormx"
+	"gorm.io/gorm"
+)
+
+// AssertRowCount kiểm tra số dòng của bảng thỏa điều kiện.
+// table là tên bảng hoặc model (model có soft delete sẽ bỏ qua dòng đã xóa), conds theo cú pháp Where của GORM:
+//
+//	gormxtest.AssertRowCount(t, ctx, ds, "user_tbl", 1, "email = ?", "a@example.com")
+func AssertRowCount(t testing.TB, ctx context.Context, ds *gormx.DataSource, table any, want int64, conds ...any) {
+	t.Helper()
+	got, err := count(ctx, ds, table, conds...)
+	if err != nil {
+		t.Fatalf("gormxtest: count %s: %v", tableName(table), err)
+	}
+	if got != want {
+		t.Errorf("gormxtest: %s%s has %d rows, want %d", tableName(table), describe(conds), got, want)
+	}
+}
+
+// AssertExists kiểm tra có ít nhất một dòng thỏa điều kiện
+func AssertExists(t testing.TB, ctx context.Context, ds *gormx.DataSource, table any, conds ...any) {
+	t.Helper()
+	got, err := count(ctx, ds, table, conds...)
+	if err != nil {
+		t.Fatalf("gormxtest: count %s: %v", tableName(table), err)
+	}
+	if got == 0 {
+		t.Errorf("gormxtest: expected a row in %s%s, found none", tableName(table), describe(conds))
+	}
+}
+
+// AssertNotExists kiểm tra không có dòng nào thỏa điều kiện
+func AssertNotExists(t testing.TB, ctx context.Context, ds *gormx.DataSource, table any, conds ...any) {
+	t.Helper()
+	AssertRowCount(t, ctx, ds, table, 0, conds...)
+}
+
+func count(ctx context.Context, ds *gormx.DataSource, table any, conds ...any) (int64, error) {
+	var db *gorm.DB
+	if name, ok := table.(string); ok {
+		db = ds.Reader(ctx).Table(name)
+	} else {
+		db = ds.Reader(ctx).Model(table)
+	}
+	if len(conds) > 0 {
+		db = db.Where(conds[0], conds[1:]...)
+	}
+	var n int64
+	err := db.Count(&n).Error
+	return n, err
+}
+
+func tableName(table any) string {
+	if name, ok := table.(string); ok {
+		return name
+	}
+	return fmt.Sprintf("%T", table)
+}
+
+func describe(conds []any) string {
+	if len(conds) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" where %v", conds)
+}
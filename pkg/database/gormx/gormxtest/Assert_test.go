@@ -0,0 +1,81 @@
+package gormxtest
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// fakeTB ghi lại lỗi thay vì làm fail test thật
+type fakeTB struct {
+	testing.TB
+	errors []string
+	fatal  bool
+}
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) Errorf(format string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeTB) Fatalf(format string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+	f.fatal = true
+	runtime.Goexit()
+}
+
+// run chạy fn trên goroutine riêng để Fatalf (Goexit) không dừng test thật
+func (f *fakeTB) run(fn func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+	<-done
+}
+
+func TestAssertHelpers(t *testing.T) {
+	ds := New(t, WithModels(&widget{}))
+	ctx := context.Background()
+	if err := ds.Create(&[]widget{{Name: "a", Price: 1}, {Name: "b", Price: 2}}).Error; err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		assert func(t testing.TB)
+		failed bool
+	}{
+		{"row count", func(t testing.TB) { AssertRowCount(t, ctx, ds, &widget{}, 2) }, false},
+		{"row count mismatch", func(t testing.TB) { AssertRowCount(t, ctx, ds, "widgets", 1, "price > ?", 0) }, true},
+		{"exists", func(t testing.TB) { AssertExists(t, ctx, ds, &widget{}, "name = ?", "b") }, false},
+		{"exists missing", func(t testing.TB) { AssertExists(t, ctx, ds, &widget{}, "name = ?", "c") }, true},
+		{"not exists", func(t testing.TB) { AssertNotExists(t, ctx, ds, &widget{}, "price > ?", 5) }, false},
+		{"not exists present", func(t testing.TB) { AssertNotExists(t, ctx, ds, &widget{}, "name = ?", "a") }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ft := &fakeTB{TB: t}
+			ft.run(func() { tt.assert(ft) })
+			if got := len(ft.errors) > 0; got != tt.failed {
+				t.Fatalf("failed = %v, want %v (errors: %v)", got, tt.failed, ft.errors)
+			}
+			if ft.fatal {
+				t.Fatalf("unexpected fatal: %v", ft.errors)
+			}
+		})
+	}
+}
+
+func TestAssertReportsQueryError(t *testing.T) {
+	ds := New(t)
+
+	ft := &fakeTB{TB: t}
+	ft.run(func() { AssertRowCount(ft, context.Background(), ds, "no_such_table", 0) })
+	if !ft.fatal || !strings.Contains(ft.errors[0], "no_such_table") {
+		t.Fatalf("expected fatal count error, got %v", ft.errors)
+	}
+}
@@ -0,0 +1,150 @@
+// Package gormxtest hỗ trợ viết test cho repository gormx không cần database thật:
+// SQLite in-memory (pure Go), transaction rollback sau mỗi test, fixture YAML và các hàm assert
+package gormxtest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync/atomic"
+	"testing"
+
+	"github.io/xhkzeroone/goframex/pkg/database/gormx"
+)
+
+type Option func(o *options)
+
+type options struct {
+	models []any
+	dsOpts []gormx.Option
+	debug  bool
+	config *gormx.Config
+}
+
+// WithModels auto-migrate các model khi tạo DataSource
+func WithModels(models ...any) Option {
+	return func(o *options) {
+		o.models = append(o.models, models...)
+	}
+}
+
+// WithDataSourceOptions truyền thêm option cho gormx.Open (tenant resolver, tracer...)
+func WithDataSourceOptions(opts ...gormx.Option) Option {
+	return func(o *options) {
+		o.dsOpts = append(o.dsOpts, opts...)
+	}
+}
+
+// WithConfig dùng Config riêng (slow_threshold, cursor_secret...), các field kết nối bị ghi đè
+func WithConfig(cfg *gormx.Config) Option {
+	return func(o *options) {
+		o.config = cfg
+	}
+}
+
+// WithDebug in toàn bộ câu SQL ra log
+func WithDebug() Option {
+	return func(o *options) {
+		o.debug = true
+	}
+}
+
+// sqliteDriver là tên driver database/sql do glebarez/go-sqlite đăng ký
+const sqliteDriver = "sqlite"
+
+var (
+	dbSeq       atomic.Int64
+	unsafeChars = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+	errRollback = errors.New("gormxtest: rollback")
+)
+
+// New tạo DataSource trên SQLite in-memory riêng cho test, được đóng khi test kết thúc.
+// Mỗi lần gọi là một database độc lập nên an toàn với t.Parallel
+func New(t testing.TB, opts ...Option) *gormx.DataSource {
+	t.Helper()
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	cfg := gormx.Config{}
+	if o.config != nil {
+		cfg = *o.config
+	}
+	name := fmt.Sprintf("%s_%d", unsafeChars.ReplaceAllString(t.Name(), "_"), dbSeq.Add(1))
+	cfg.Driver = gormx.DriverSQLite
+	cfg.DSN = fmt.Sprintf("file:%s?mode=memory&cache=shared&_pragma=foreign_keys(1)", name)
+	cfg.Replicas = nil
+	cfg.Debug = o.debug
+	if cfg.CursorSecret == "" {
+		cfg.CursorSecret = "gormxtest"
+	}
+
+	ds, err := gormx.Open(&cfg, o.dsOpts...)
+	if err != nil {
+		t.Fatalf("gormxtest: open sqlite: %v", err)
+	}
+	// Database in-memory bị xóa khi kết nối cuối cùng đóng, giữ một kết nối riêng ngoài pool tới hết test
+	// để pool của ds vẫn dùng đủ max_open_conns (kể cả 1)
+	keepAlive, err := sql.Open(sqliteDriver, cfg.DSN)
+	if err == nil {
+		err = keepAlive.Ping()
+	}
+	if err != nil {
+		_ = ds.Close()
+		t.Fatalf("gormxtest: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = keepAlive.Close()
+		if err := ds.Close(); err != nil {
+			t.Errorf("gormxtest: close datasource: %v", err)
+		}
+	})
+
+	if len(o.models) > 0 {
+		if err := ds.DB.WithContext(gormx.WithoutTenant(context.Background())).AutoMigrate(o.models...); err != nil {
+			t.Fatalf("gormxtest: auto migrate: %v", err)
+		}
+	}
+	return ds
+}
+
+// Begin mở transaction trên ds và trả về ctx gắn transaction đó, transaction bị rollback khi test kết thúc.
+// Repository dùng ctx này sẽ ghi vào transaction, Transactional lồng bên trong trở thành savepoint
+// nên hook AfterCommit không bao giờ chạy. SQLite chỉ cho một transaction ghi tại một thời điểm,
+// test chạy song song nên dùng DataSource riêng (New)
+func Begin(t testing.TB, ds *gormx.DataSource) context.Context {
+	t.Helper()
+	return BeginContext(t, context.Background(), ds)
+}
+
+// BeginContext giống Begin nhưng dùng parent làm ctx gốc (ví dụ ctx đã gắn tenant hoặc actor)
+func BeginContext(t testing.TB, parent context.Context, ds *gormx.DataSource) context.Context {
+	t.Helper()
+	ready := make(chan context.Context, 1)
+	release := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- gormx.Transactional(parent, ds, func(ctx context.Context) error {
+			ready <- ctx
+			<-release
+			return errRollback
+		})
+	}()
+
+	select {
+	case ctx := <-ready:
+		t.Cleanup(func() {
+			close(release)
+			if err := <-done; !errors.Is(err, errRollback) {
+				t.Errorf("gormxtest: rollback: %v", err)
+			}
+		})
+		return ctx
+	case err := <-done:
+		t.Fatalf("gormxtest: begin transaction: %v", err)
+		return nil
+	}
+}
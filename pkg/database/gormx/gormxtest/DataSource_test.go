@@ -0,0 +1,79 @@
+package gormxtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.io/xhkzeroone/goframex/pkg/database/gormx"
+)
+
+type widget struct {
+	ID    uint   `gorm:"primaryKey"`
+	Name  string `gorm:"size:64;not null"`
+	Price int
+}
+
+func TestNewIsolatesDatabases(t *testing.T) {
+	a := New(t, WithModels(&widget{}))
+	b := New(t, WithModels(&widget{}))
+
+	if err := a.Create(&widget{Name: "a"}).Error; err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	AssertRowCount(t, context.Background(), a, &widget{}, 1)
+	AssertRowCount(t, context.Background(), b, &widget{}, 0)
+}
+
+func TestNewWithSingleConnection(t *testing.T) {
+	ds := New(t, WithModels(&widget{}), WithConfig(&gormx.Config{MaxOpenConns: 1}))
+
+	// Transaction giữ kết nối duy nhất của pool, không được phụ thuộc vào kết nối giữ database
+	done := make(chan error, 1)
+	go func() {
+		done <- gormx.Transactional(context.Background(), ds, func(ctx context.Context) error {
+			return ds.Writer(ctx).Create(&widget{Name: "a"}).Error
+		})
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("transactional: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("transaction blocked on a single-connection pool")
+	}
+	AssertRowCount(t, context.Background(), ds, &widget{}, 1)
+}
+
+func TestBeginRollsBackOnCleanup(t *testing.T) {
+	ds := New(t, WithModels(&widget{}))
+
+	t.Run("write", func(t *testing.T) {
+		ctx := Begin(t, ds)
+		if err := ds.Writer(ctx).Create(&widget{Name: "a"}).Error; err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		AssertRowCount(t, ctx, ds, &widget{}, 1)
+	})
+
+	AssertRowCount(t, context.Background(), ds, &widget{}, 0)
+}
+
+func TestBeginNestedTransactionIsSavepoint(t *testing.T) {
+	ds := New(t, WithModels(&widget{}))
+	ctx := Begin(t, ds)
+
+	committed := false
+	err := gormx.Transactional(ctx, ds, func(ctx context.Context) error {
+		gormx.AfterCommit(ctx, func(context.Context) { committed = true })
+		return ds.Writer(ctx).Create(&widget{Name: "a"}).Error
+	})
+	if err != nil {
+		t.Fatalf("transactional: %v", err)
+	}
+	if committed {
+		t.Error("AfterCommit ran inside the test transaction")
+	}
+	AssertExists(t, ctx, ds, &widget{}, "name = ?", "a")
+}
@@ -0,0 +1,80 @@
+package gormxtest
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"testing"
+
+	"github.io/xhkzeroone/goframex/pkg/database/gormx"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadFixtures nạp fixture từ các file YAML. Mỗi file là map tên bảng -> danh sách dòng,
+// bảng được nạp theo thứ tự xuất hiện trong file để tôn trọng khóa ngoại:
+//
+//	user_tbl:
+//	  - id: 8f0c...
+//	    email: a@example.com
+//	order_tbl:
+//	  - user_id: 8f0c...
+//
+// Dùng ctx từ Begin để fixture bị rollback cùng test
+func LoadFixtures(t testing.TB, ctx context.Context, ds *gormx.DataSource, paths ...string) {
+	t.Helper()
+	LoadFixturesFS(t, ctx, ds, os.DirFS("."), paths...)
+}
+
+// LoadFixturesFS giống LoadFixtures nhưng đọc từ fsys (ví dụ embed.FS), patterns theo cú pháp fs.Glob
+func LoadFixturesFS(t testing.TB, ctx context.Context, ds *gormx.DataSource, fsys fs.FS, patterns ...string) {
+	t.Helper()
+	for _, pattern := range patterns {
+		files, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			t.Fatalf("gormxtest: fixture pattern %q: %v", pattern, err)
+		}
+		if len(files) == 0 {
+			t.Fatalf("gormxtest: no fixture matches %q", pattern)
+		}
+		for _, file := range files {
+			data, err := fs.ReadFile(fsys, file)
+			if err != nil {
+				t.Fatalf("gormxtest: read fixture %s: %v", file, err)
+			}
+			if err := insertFixture(ctx, ds, data); err != nil {
+				t.Fatalf("gormxtest: load fixture %s: %v", file, err)
+			}
+		}
+	}
+}
+
+// insertFixture ghi từng dòng của fixture, giữ thứ tự bảng như trong file
+func insertFixture(ctx context.Context, ds *gormx.DataSource, data []byte) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("fixture must be a map of table name to rows")
+	}
+
+	db := ds.Writer(gormx.WithoutTenant(ctx))
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		table := root.Content[i].Value
+		var rows []map[string]any
+		if err := root.Content[i+1].Decode(&rows); err != nil {
+			return fmt.Errorf("table %s: %w", table, err)
+		}
+		for n, row := range rows {
+			if err := db.Table(table).Create(row).Error; err != nil {
+				return fmt.Errorf("table %s row %d: %w", table, n, err)
+			}
+		}
+	}
+	return nil
+}
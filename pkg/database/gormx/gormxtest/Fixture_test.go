@@ -0,0 +1,80 @@
+package gormxtest
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+)
+
+type author struct {
+	ID   uint   `gorm:"primaryKey"`
+	Name string `gorm:"size:64;not null"`
+}
+
+type book struct {
+	ID       uint   `gorm:"primaryKey"`
+	AuthorID uint   `gorm:"not null"`
+	Author   author `gorm:"constraint:OnDelete:RESTRICT"`
+	Title    string `gorm:"size:128"`
+	Pages    int
+}
+
+var fixtures = fstest.MapFS{
+	"fixtures/library.yml": {Data: []byte(`
+authors:
+  - id: 1
+    name: Tolstoy
+  - id: 2
+    name: Chekhov
+books:
+  - id: 10
+    author_id: 1
+    title: War and Peace
+    pages: 1225
+  - id: 11
+    author_id: 2
+    title: The Steppe
+    pages: 120
+`)},
+	"fixtures/extra.yml": {Data: []byte(`
+books:
+  - id: 12
+    author_id: 1
+    title: Anna Karenina
+    pages: 864
+`)},
+}
+
+func TestLoadFixturesFS(t *testing.T) {
+	ds := New(t, WithModels(&author{}, &book{}))
+	ctx := Begin(t, ds)
+
+	// Bảng cha đứng trước trong file nên khóa ngoại của books được thỏa
+	LoadFixturesFS(t, ctx, ds, fixtures, "fixtures/library.yml", "fixtures/extra.yml")
+
+	AssertRowCount(t, ctx, ds, "authors", 2)
+	AssertRowCount(t, ctx, ds, &book{}, 2, "author_id = ?", 1)
+	AssertExists(t, ctx, ds, &book{}, "title = ? AND pages = ?", "The Steppe", 120)
+}
+
+func TestLoadFixturesFSGlob(t *testing.T) {
+	ds := New(t, WithModels(&author{}, &book{}))
+	ctx := Begin(t, ds)
+
+	// fs.Glob trả về theo thứ tự tên file: extra.yml được nạp trước library.yml nên thiếu author
+	ft := &fakeTB{TB: t}
+	ft.run(func() { LoadFixturesFS(ft, ctx, ds, fixtures, "fixtures/*.yml") })
+	if !ft.fatal {
+		t.Fatal("expected foreign key violation to fail the test")
+	}
+}
+
+func TestLoadFixturesFSNoMatch(t *testing.T) {
+	ds := New(t)
+
+	ft := &fakeTB{TB: t}
+	ft.run(func() { LoadFixturesFS(ft, context.Background(), ds, fixtures, "missing/*.yml") })
+	if !ft.fatal {
+		t.Fatal("expected a missing fixture to fail the test")
+	}
+}
@@ -0,0 +1,102 @@
+package migrate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.io/xhkzeroone/goframex/pkg/database/gormx"
+)
+
+// GINIndex mô tả index GIN trên Postgres cho cột jsonb, mảng, tsvector hoặc biểu thức full-text
+type GINIndex struct {
+	// Name bỏ trống sẽ dùng idx_<table>_<column>_gin
+	Name  string
+	Table string
+	// Column là cột được đánh index, bỏ qua nếu có Expression
+	Column string
+	// Expression là biểu thức được đánh index, ví dụ gormx.TSText(...).IndexExpression()
+	Expression string
+	// OpClass ví dụ jsonb_path_ops (chỉ hỗ trợ @>, index nhỏ hơn) hoặc gin_trgm_ops
+	OpClass string
+}
+
+// JSONBIndex tạo index GIN cho cột jsonb dùng với JSONContains, JSONHasKey.
+// pathOps dùng jsonb_path_ops: nhỏ và nhanh hơn nhưng chỉ phục vụ JSONContains
+func JSONBIndex(table, column string, pathOps bool) GINIndex {
+	idx := GINIndex{Table: table, Column: column}
+	if pathOps {
+		idx.OpClass = "jsonb_path_ops"
+	}
+	return idx
+}
+
+// ArrayIndex tạo index GIN cho cột mảng dùng với ArrayOverlaps, ArrayContainsAll
+func ArrayIndex(table, column string) GINIndex {
+	return GINIndex{Table: table, Column: column}
+}
+
+// TextSearchIndex tạo index GIN khớp với biểu thức của doc, dùng với gormx.TextSearch và gormx.RankBy
+func TextSearchIndex(table string, doc gormx.TSDocument) GINIndex {
+	if col := doc.VectorColumn(); col != "" {
+		return GINIndex{Table: table, Column: col}
+	}
+	return GINIndex{Table: table, Expression: doc.IndexExpression()}
+}
+
+func (i GINIndex) name() string {
+	if i.Name != "" {
+		return i.Name
+	}
+	table := i.Table[strings.LastIndex(i.Table, ".")+1:]
+	if i.Column != "" {
+		return fmt.Sprintf("idx_%s_%s_gin", table, i.Column)
+	}
+	return fmt.Sprintf("idx_%s_fts_gin", table)
+}
+
+// UpSQL trả về câu CREATE INDEX
+func (i GINIndex) UpSQL() string {
+	target := i.Expression
+	if target == "" {
+		target = quoteIdent(i.Column)
+	} else {
+		target = "(" + target + ")"
+	}
+	if i.OpClass != "" {
+		target += " " + i.OpClass
+	}
+	return fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s USING GIN (%s)", quoteIdent(i.name()), quoteIdent(i.Table), target)
+}
+
+// DownSQL trả về câu DROP INDEX, index nằm cùng schema với bảng
+func (i GINIndex) DownSQL() string {
+	name := quoteIdent(i.name())
+	if dot := strings.LastIndex(i.Table, "."); dot >= 0 {
+		name = quoteIdent(i.Table[:dot]) + "." + name
+	}
+	return "DROP INDEX IF EXISTS " + name
+}
+
+// GINIndexMigration tạo migration cho các index, dùng với Migrator.Add
+func GINIndexMigration(version int64, name string, indexes ...GINIndex) *Migration {
+	up := make([]string, len(indexes))
+	down := make([]string, len(indexes))
+	for n, idx := range indexes {
+		up[n] = idx.UpSQL() + ";"
+		down[len(indexes)-1-n] = idx.DownSQL() + ";"
+	}
+	return &Migration{
+		Version: version,
+		Name:    name,
+		UpSQL:   strings.Join(up, "\n"),
+		DownSQL: strings.Join(down, "\n"),
+	}
+}
+
+func quoteIdent(name string) string {
+	parts := strings.Split(name, ".")
+	for i, p := range parts {
+		parts[i] = `"` + strings.ReplaceAll(p, `"`, `""`) + `"`
+	}
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,94 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"math"
+	"time"
+)
+
+// ErrLockTimeout trả về khi không lấy được lock migrate (replica khác đang chạy migrate)
+var ErrLockTimeout = errors.New("migrate: timeout waiting for migration lock")
+
+// acquireLock lấy advisory lock trên một kết nối riêng, lock tồn tại tới khi gọi hàm unlock trả về.
+// Postgres: pg_advisory_lock, MySQL: GET_LOCK, SQL Server: sp_getapplock, SQLite: không cần lock.
+// Mỗi schema (tenant) có lock riêng nên các tenant migrate song song được
+func (m *Migrator) acquireLock(ctx context.Context, schemaName string) (func(), error) {
+	switch m.db.Dialector.Name() {
+	case "postgres", "mysql", "sqlserver":
+	default:
+		// Không giữ kết nối riêng cho driver không có advisory lock, tránh chiếm kết nối duy nhất
+		// của pool (ví dụ SQLite với MaxOpenConns=1) khiến transaction migrate chờ mãi
+		return func() {}, nil
+	}
+
+	sqlDB, err := m.db.DB()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lockCtx, cancel := context.WithTimeout(ctx, m.lockTimeout)
+	defer cancel()
+
+	name := "goframex_migrate:" + m.table
+	if schemaName != "" {
+		name += ":" + schemaName
+	}
+	var unlockSQL string
+	var unlockArg any
+	switch m.db.Dialector.Name() {
+	case "postgres":
+		key := lockKey(name)
+		_, err = conn.ExecContext(lockCtx, "SELECT pg_advisory_lock($1)", key)
+		unlockSQL, unlockArg = "SELECT pg_advisory_unlock($1)", key
+	case "mysql":
+		var got sql.NullInt64
+		err = conn.QueryRowContext(lockCtx, "SELECT GET_LOCK(?, ?)", name, int64(math.Ceil(m.lockTimeout.Seconds()))).Scan(&got)
+		if err == nil && (!got.Valid || got.Int64 != 1) {
+			err = ErrLockTimeout
+		}
+		unlockSQL, unlockArg = "SELECT RELEASE_LOCK(?)", name
+	case "sqlserver":
+		var code int
+		err = conn.QueryRowContext(lockCtx,
+			"DECLARE @r int; EXEC @r = sp_getapplock @Resource = @p1, @LockMode = 'Exclusive', @LockOwner = 'Session', @LockTimeout = @p2; SELECT @r",
+			name, m.lockTimeout.Milliseconds()).Scan(&code)
+		if err == nil && code < 0 {
+			err = ErrLockTimeout
+		}
+		unlockSQL, unlockArg = "EXEC sp_releaseapplock @Resource = @p1, @LockOwner = 'Session'", name
+	}
+	if err != nil {
+		_ = conn.Close()
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, ErrLockTimeout
+		}
+		return nil, fmt.Errorf("migrate: acquire lock: %w", err)
+	}
+
+	return func() {
+		if unlockSQL != "" {
+			// Dùng context riêng để vẫn unlock được khi ctx đã bị hủy
+			unlockCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if _, err := conn.ExecContext(unlockCtx, unlockSQL, unlockArg); err != nil {
+				log.Printf("migrate: release lock failed: %v", err)
+			}
+		}
+		_ = conn.Close()
+	}, nil
+}
+
+func lockKey(name string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(name))
+	return int64(h.Sum64())
+}
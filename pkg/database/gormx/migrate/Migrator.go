@@ -0,0 +1,380 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.io/xhkzeroone/goframex/pkg/database/gormx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// DefaultTable là bảng lưu các migration đã chạy
+const DefaultTable = "schema_migrations"
+
+var (
+	// ErrIrreversible trả về khi rollback migration không có Down
+	ErrIrreversible = errors.New("migrate: migration has no down step")
+	// ErrUnknownVersion trả về khi To nhận version không có trong danh sách migration
+	ErrUnknownVersion = errors.New("migrate: unknown migration version")
+	// ErrTenantUnsupported trả về khi ctx có tenant nhưng DataSource không dùng schema-per-tenant trên Postgres
+	ErrTenantUnsupported = errors.New("migrate: tenant migrations require schema-per-tenant on postgres")
+)
+
+type Option func(m *Migrator)
+
+// WithTable đổi tên bảng lưu lịch sử migration (mặc định schema_migrations)
+func WithTable(table string) Option {
+	return func(m *Migrator) {
+		m.table = table
+	}
+}
+
+// WithDryRun chỉ in SQL sẽ chạy ra w, không thay đổi database
+func WithDryRun(w io.Writer) Option {
+	return func(m *Migrator) {
+		m.dryRun = w
+	}
+}
+
+// WithLockTimeout thời gian tối đa chờ lock khi replica khác đang migrate (mặc định 5 phút)
+func WithLockTimeout(timeout time.Duration) Option {
+	return func(m *Migrator) {
+		m.lockTimeout = timeout
+	}
+}
+
+// Migrator chạy migration theo thứ tự version trên primary của DataSource
+type Migrator struct {
+	ds          *gormx.DataSource
+	db          *gorm.DB
+	table       string
+	dryRun      io.Writer
+	lockTimeout time.Duration
+	migrations  []*Migration
+}
+
+// MigrationStatus là trạng thái của một migration
+type MigrationStatus struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"appliedAt,omitempty"`
+	// Missing: đã chạy trong database nhưng không còn trong source
+	Missing bool `json:"missing,omitempty"`
+}
+
+type schemaMigration struct {
+	Version   int64     `gorm:"column:version;primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"column:name;size:255"`
+	AppliedAt time.Time `gorm:"column:applied_at"`
+}
+
+func New(ds *gormx.DataSource, opts ...Option) *Migrator {
+	m := &Migrator{
+		ds:          ds,
+		db:          ds.DB,
+		table:       DefaultTable,
+		lockTimeout: 5 * time.Minute,
+	}
+	for _, o := range opts {
+		o(m)
+	}
+	return m
+}
+
+// Add đăng ký migration viết bằng Go hoặc SQL
+func (m *Migrator) Add(migrations ...*Migration) *Migrator {
+	m.migrations = append(m.migrations, migrations...)
+	return m
+}
+
+// AddFS đăng ký các file <version>_<name>.up.sql / .down.sql trong thư mục dir của fsys.
+// Mỗi file được Exec một lần, với MySQL cần bật multiStatements nếu file có nhiều câu lệnh
+func (m *Migrator) AddFS(fsys fs.FS, dir string) error {
+	migrations, err := loadFS(fsys, dir)
+	if err != nil {
+		return err
+	}
+	m.migrations = append(m.migrations, migrations...)
+	return nil
+}
+
+// Up chạy toàn bộ migration chưa được áp dụng
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.run(ctx, func(ctx context.Context, migrations []*Migration, applied map[int64]schemaMigration) error {
+		for _, mig := range migrations {
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			if err := m.apply(ctx, mig, true); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Down rollback n migration được áp dụng gần nhất (theo version)
+func (m *Migrator) Down(ctx context.Context, n int) error {
+	return m.run(ctx, func(ctx context.Context, migrations []*Migration, applied map[int64]schemaMigration) error {
+		for i := len(migrations) - 1; i >= 0 && n > 0; i-- {
+			if _, ok := applied[migrations[i].Version]; !ok {
+				continue
+			}
+			if err := m.apply(ctx, migrations[i], false); err != nil {
+				return err
+			}
+			n--
+		}
+		return nil
+	})
+}
+
+// To đưa database về đúng version: chạy các migration <= version chưa áp dụng
+// và rollback các migration > version đã áp dụng. version = 0 rollback toàn bộ
+func (m *Migrator) To(ctx context.Context, version int64) error {
+	return m.run(ctx, func(ctx context.Context, migrations []*Migration, applied map[int64]schemaMigration) error {
+		if version != 0 && !containsVersion(migrations, version) {
+			return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+		}
+		for i := len(migrations) - 1; i >= 0; i-- {
+			mig := migrations[i]
+			if _, ok := applied[mig.Version]; ok && mig.Version > version {
+				if err := m.apply(ctx, mig, false); err != nil {
+					return err
+				}
+			}
+		}
+		for _, mig := range migrations {
+			if _, ok := applied[mig.Version]; !ok && mig.Version <= version {
+				if err := m.apply(ctx, mig, true); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// Status trả về trạng thái của mọi migration, sắp xếp theo version
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	ctx = tenantContext(ctx)
+	if _, err := m.tenantSchema(ctx); err != nil {
+		return nil, err
+	}
+	migrations, err := m.sorted()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]MigrationStatus, 0, len(migrations))
+	for _, mig := range migrations {
+		s := MigrationStatus{Version: mig.Version, Name: mig.Name}
+		if rec, ok := applied[mig.Version]; ok {
+			s.Applied = true
+			s.AppliedAt = &rec.AppliedAt
+			delete(applied, mig.Version)
+		}
+		out = append(out, s)
+	}
+	for _, rec := range applied {
+		out = append(out, MigrationStatus{Version: rec.Version, Name: rec.Name, Applied: true, AppliedAt: &rec.AppliedAt, Missing: true})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+// run lấy lock, đảm bảo bảng lịch sử tồn tại rồi chạy fn
+func (m *Migrator) run(ctx context.Context, fn func(ctx context.Context, migrations []*Migration, applied map[int64]schemaMigration) error) error {
+	ctx = tenantContext(ctx)
+	schemaName, err := m.tenantSchema(ctx)
+	if err != nil {
+		return err
+	}
+	migrations, err := m.sorted()
+	if err != nil {
+		return err
+	}
+
+	if m.dryRun != nil {
+		if schemaName != "" {
+			fmt.Fprintf(m.dryRun, "SET search_path TO \"%s\", public;\n", schemaName)
+		}
+	} else {
+		unlock, err := m.acquireLock(ctx, schemaName)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		// search_path chỉ trỏ tới schema đã tồn tại, nếu không bảng sẽ rơi vào public
+		if schemaName != "" {
+			if err := m.db.WithContext(ctx).Exec(fmt.Sprintf(`CREATE SCHEMA IF NOT EXISTS "%s"`, schemaName)).Error; err != nil {
+				return fmt.Errorf("migrate: create schema %s: %w", schemaName, err)
+			}
+		}
+		if err := m.transaction(ctx, func(tx *gorm.DB) error {
+			return tx.Table(m.table).AutoMigrate(&schemaMigration{})
+		}); err != nil {
+			return fmt.Errorf("migrate: create %s: %w", m.table, err)
+		}
+	}
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+	return fn(ctx, migrations, applied)
+}
+
+// apply chạy một migration cùng với việc ghi/xóa bản ghi lịch sử trong cùng transaction
+func (m *Migrator) apply(ctx context.Context, mig *Migration, up bool) error {
+	direction := "up"
+	if !up {
+		direction = "down"
+		if !mig.hasDown() {
+			return fmt.Errorf("%w: %d_%s", ErrIrreversible, mig.Version, mig.Name)
+		}
+	}
+
+	step := func(tx *gorm.DB) error {
+		var err error
+		switch {
+		case up && mig.Up != nil:
+			err = mig.Up(tx)
+		case up:
+			err = tx.Exec(mig.UpSQL).Error
+		case mig.Down != nil:
+			err = mig.Down(tx)
+		default:
+			err = tx.Exec(mig.DownSQL).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		if up {
+			return tx.Table(m.table).Create(&schemaMigration{Version: mig.Version, Name: mig.Name, AppliedAt: time.Now().UTC()}).Error
+		}
+		return tx.Table(m.table).Where("version = ?", mig.Version).Delete(&schemaMigration{}).Error
+	}
+
+	if m.dryRun != nil {
+		fmt.Fprintf(m.dryRun, "-- %s %d_%s\n", direction, mig.Version, mig.Name)
+		tx := m.db.Session(&gorm.Session{DryRun: true, Context: ctx, Logger: &sqlPrinter{w: m.dryRun}})
+		return step(tx)
+	}
+
+	start := time.Now()
+	if err := m.transaction(ctx, step); err != nil {
+		return fmt.Errorf("migrate: %s %d_%s: %w", direction, mig.Version, mig.Name, err)
+	}
+	log.Printf("migrate: %s %d_%s done in %s", direction, mig.Version, mig.Name, time.Since(start))
+	return nil
+}
+
+// applied đọc các version đã chạy, bảng chưa tồn tại coi như chưa có migration nào
+func (m *Migrator) applied(ctx context.Context) (map[int64]schemaMigration, error) {
+	out := make(map[int64]schemaMigration)
+	err := m.transaction(ctx, func(tx *gorm.DB) error {
+		if !tx.Migrator().HasTable(m.table) {
+			return nil
+		}
+		var records []schemaMigration
+		if err := tx.Table(m.table).Find(&records).Error; err != nil {
+			return err
+		}
+		for _, r := range records {
+			out[r.Version] = r
+		}
+		return nil
+	})
+	return out, err
+}
+
+// transaction chạy fn qua gormx.Transactional để transaction của tenant được đặt search_path
+// về schema của tenant, raw SQL và bảng lịch sử đều nằm trong schema đó
+func (m *Migrator) transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return gormx.Transactional(ctx, m.ds, func(ctx context.Context) error {
+		return fn(m.ds.Writer(ctx))
+	})
+}
+
+// tenantSchema trả về schema của tenant trong ctx, rỗng khi migrate schema dùng chung.
+// Chỉ Postgres có search_path nên tenant với driver khác bị từ chối
+func (m *Migrator) tenantSchema(ctx context.Context) (string, error) {
+	if _, ok := gormx.TenantFromContext(ctx); !ok {
+		return "", nil
+	}
+	schemaName, err := m.ds.TenantSchema(ctx)
+	if err != nil {
+		return "", err
+	}
+	if schemaName == "" || m.db.Dialector.Name() != gormx.DriverPostgres {
+		return "", ErrTenantUnsupported
+	}
+	return schemaName, nil
+}
+
+// sorted sắp xếp migration theo version và kiểm tra trùng lặp
+func (m *Migrator) sorted() ([]*Migration, error) {
+	out := append([]*Migration(nil), m.migrations...)
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	for i, mig := range out {
+		if mig.Version <= 0 {
+			return nil, fmt.Errorf("migrate: version of %q must be positive", mig.Name)
+		}
+		if !mig.hasUp() {
+			return nil, fmt.Errorf("migrate: %d_%s has no up step", mig.Version, mig.Name)
+		}
+		if i > 0 && out[i-1].Version == mig.Version {
+			return nil, fmt.Errorf("migrate: duplicate version %d", mig.Version)
+		}
+	}
+	return out, nil
+}
+
+// tenantContext: không có tenant trong ctx thì migrate schema dùng chung, bỏ qua guard tenant của gormx.
+// Có tenant (schema-per-tenant, Postgres) thì migration và lịch sử chạy với search_path của tenant đó
+func tenantContext(ctx context.Context) context.Context {
+	if _, ok := gormx.TenantFromContext(ctx); ok {
+		return ctx
+	}
+	return gormx.WithoutTenant(ctx)
+}
+
+func containsVersion(migrations []*Migration, version int64) bool {
+	for _, mig := range migrations {
+		if mig.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// sqlPrinter là logger GORM dùng cho dry-run, in câu lệnh SQL đã được dựng
+type sqlPrinter struct {
+	w io.Writer
+}
+
+func (p *sqlPrinter) LogMode(logger.LogLevel) logger.Interface      { return p }
+func (p *sqlPrinter) Info(context.Context, string, ...interface{})  {}
+func (p *sqlPrinter) Warn(context.Context, string, ...interface{})  {}
+func (p *sqlPrinter) Error(context.Context, string, ...interface{}) {}
+
+func (p *sqlPrinter) Trace(_ context.Context, _ time.Time, fc func() (string, int64), _ error) {
+	sql, _ := fc()
+	fmt.Fprintf(p.w, "%s;\n", strings.TrimRight(sql, "; \t\r\n"))
+}
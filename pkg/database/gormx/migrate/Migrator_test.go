@@ -0,0 +1,147 @@
+package migrate_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.io/xhkzeroone/goframex/pkg/database/gormx"
+	"github.io/xhkzeroone/goframex/pkg/database/gormx/gormxtest"
+	"github.io/xhkzeroone/goframex/pkg/database/gormx/migrate"
+	"gorm.io/gorm"
+)
+
+var migrationFS = fstest.MapFS{
+	"sql/0001_create_authors.up.sql":   {Data: []byte("CREATE TABLE authors (id INTEGER PRIMARY KEY, name TEXT NOT NULL)")},
+	"sql/0001_create_authors.down.sql": {Data: []byte("DROP TABLE authors")},
+	"sql/0002_create_books.up.sql":     {Data: []byte("CREATE TABLE books (id INTEGER PRIMARY KEY, author_id INTEGER NOT NULL)")},
+	"sql/0002_create_books.down.sql":   {Data: []byte("DROP TABLE books")},
+	"sql/README.md":                    {Data: []byte("ignored")},
+}
+
+func newMigrator(t *testing.T, opts ...migrate.Option) (*gormx.DataSource, *migrate.Migrator) {
+	t.Helper()
+	ds := gormxtest.New(t)
+	m := migrate.New(ds, opts...)
+	if err := m.AddFS(migrationFS, "sql"); err != nil {
+		t.Fatalf("add fs: %v", err)
+	}
+	return ds, m
+}
+
+func applied(t *testing.T, m *migrate.Migrator) []int64 {
+	t.Helper()
+	status, err := m.Status(context.Background())
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	var out []int64
+	for _, s := range status {
+		if s.Applied {
+			out = append(out, s.Version)
+		}
+	}
+	return out
+}
+
+func TestMigratorTracksAppliedVersions(t *testing.T) {
+	ctx := context.Background()
+	ds, m := newMigrator(t)
+
+	if got := applied(t, m); len(got) != 0 {
+		t.Fatalf("applied before Up = %v", got)
+	}
+	if err := m.Up(ctx); err != nil {
+		t.Fatalf("up: %v", err)
+	}
+	if got := applied(t, m); len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Fatalf("applied = %v, want [1 2]", got)
+	}
+	// Up lần hai không chạy lại migration đã áp dụng (CREATE TABLE sẽ lỗi nếu chạy lại)
+	if err := m.Up(ctx); err != nil {
+		t.Fatalf("second up: %v", err)
+	}
+
+	if err := m.Down(ctx, 1); err != nil {
+		t.Fatalf("down: %v", err)
+	}
+	if got := applied(t, m); len(got) != 1 || got[0] != 1 {
+		t.Fatalf("applied after down = %v, want [1]", got)
+	}
+	if ds.Migrator().HasTable("books") || !ds.Migrator().HasTable("authors") {
+		t.Error("down did not drop only books")
+	}
+
+	if err := m.To(ctx, 2); err != nil {
+		t.Fatalf("to 2: %v", err)
+	}
+	if err := m.To(ctx, 0); err != nil {
+		t.Fatalf("to 0: %v", err)
+	}
+	if got := applied(t, m); len(got) != 0 || ds.Migrator().HasTable("authors") {
+		t.Fatalf("applied after To(0) = %v", got)
+	}
+	if err := m.To(ctx, 7); !errors.Is(err, migrate.ErrUnknownVersion) {
+		t.Errorf("To unknown version err = %v", err)
+	}
+}
+
+func TestMigratorRollsBackFailedMigration(t *testing.T) {
+	ctx := context.Background()
+	ds, m := newMigrator(t)
+	m.Add(&migrate.Migration{
+		Version: 3,
+		Name:    "broken",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Exec("CREATE TABLE reviews (id INTEGER PRIMARY KEY)").Error; err != nil {
+				return err
+			}
+			return tx.Exec("INSERT INTO missing_table VALUES (1)").Error
+		},
+	})
+
+	err := m.Up(ctx)
+	if err == nil || !strings.Contains(err.Error(), "3_broken") {
+		t.Fatalf("up err = %v, want failure of 3_broken", err)
+	}
+	// Migration lỗi được rollback cùng bản ghi lịch sử, các migration trước vẫn giữ
+	if ds.Migrator().HasTable("reviews") {
+		t.Error("table created by the failed migration was not rolled back")
+	}
+	if got := applied(t, m); len(got) != 2 {
+		t.Fatalf("applied = %v, want [1 2]", got)
+	}
+
+	if err := m.Down(ctx, 3); !errors.Is(err, migrate.ErrIrreversible) && err != nil {
+		t.Fatalf("down: %v", err)
+	}
+}
+
+func TestMigratorIrreversibleAndDryRun(t *testing.T) {
+	ctx := context.Background()
+	var out bytes.Buffer
+	ds, m := newMigrator(t, migrate.WithDryRun(&out))
+	if err := m.Up(ctx); err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if !strings.Contains(out.String(), "CREATE TABLE books") || ds.Migrator().HasTable("authors") {
+		t.Errorf("dry run output %q, authors exists %v", out.String(), ds.Migrator().HasTable("authors"))
+	}
+
+	_, m = newMigrator(t)
+	m.Add(&migrate.Migration{Version: 3, Name: "one_way", UpSQL: "CREATE TABLE notes (id INTEGER)"})
+	if err := m.Up(ctx); err != nil {
+		t.Fatalf("up: %v", err)
+	}
+	if err := m.Down(ctx, 1); !errors.Is(err, migrate.ErrIrreversible) {
+		t.Errorf("down err = %v, want ErrIrreversible", err)
+	}
+
+	m.Add(&migrate.Migration{Version: 3, Name: "duplicate", UpSQL: "SELECT 1"})
+	if err := m.Up(ctx); err == nil || !strings.Contains(err.Error(), "duplicate version 3") {
+		t.Errorf("duplicate version err = %v", err)
+	}
+}
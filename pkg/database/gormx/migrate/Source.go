@@ -0,0 +1,73 @@
+package migrate
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"strconv"
+
+	"gorm.io/gorm"
+)
+
+// Migration là một bước thay đổi schema. Dùng UpSQL/DownSQL (từ file) hoặc Up/Down (Go function)
+type Migration struct {
+	Version int64
+	Name    string
+
+	UpSQL   string
+	DownSQL string
+
+	Up   func(tx *gorm.DB) error
+	Down func(tx *gorm.DB) error
+}
+
+func (m *Migration) hasUp() bool   { return m.Up != nil || m.UpSQL != "" }
+func (m *Migration) hasDown() bool { return m.Down != nil || m.DownSQL != "" }
+
+// fileNamePattern: <version>_<name>.up.sql / <version>_<name>.down.sql, ví dụ 0001_create_users.up.sql
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// loadFS đọc migration từ các file *.sql trong thư mục dir của fsys (thường là embed.FS)
+func loadFS(fsys fs.FS, dir string) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	var out []*Migration
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		match := fileNamePattern.FindStringSubmatch(e.Name())
+		if match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migrate: invalid version in %s: %w", e.Name(), err)
+		}
+		content, err := fs.ReadFile(fsys, path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+			out = append(out, m)
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migrate: version %d has different names %q and %q", version, m.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			m.UpSQL = string(content)
+		} else {
+			m.DownSQL = string(content)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,75 @@
+package outbox
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+var errNotListenable = errors.New("outbox: connection does not support LISTEN")
+
+// listen giữ một kết nối riêng LISTEN trên kênh của outbox và đánh thức relay mỗi khi có NOTIFY.
+// Lỗi kết nối chỉ được log, relay vẫn quét theo chu kỳ
+func (r *Relay) listen(ctx context.Context) {
+	for ctx.Err() == nil {
+		err := r.waitNotifications(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if errors.Is(err, errNotListenable) {
+			logEntry(ctx).Warnf("outbox relay: %v, falling back to polling", err)
+			return
+		}
+		logEntry(ctx).Warnf("outbox relay: listen on %s: %v", r.outbox.channel, err)
+
+		timer := time.NewTimer(r.retryInitial)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+func (r *Relay) waitNotifications(ctx context.Context) error {
+	sqlDB, err := r.outbox.ds.DB.DB()
+	if err != nil {
+		return err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var listenErr error
+	rawErr := conn.Raw(func(driverConn any) error {
+		c, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			listenErr = errNotListenable
+			return nil
+		}
+		pg := c.Conn()
+		if _, listenErr = pg.Exec(ctx, "LISTEN "+pgx.Identifier{r.outbox.channel}.Sanitize()); listenErr != nil {
+			return driver.ErrBadConn
+		}
+		// Event ghi trong lúc relay chưa LISTEN sẽ được lượt quét kế tiếp xử lý
+		r.notify()
+		for {
+			if _, listenErr = pg.WaitForNotification(ctx); listenErr != nil {
+				// Bỏ kết nối thay vì trả về pool vì vẫn còn đang LISTEN
+				return driver.ErrBadConn
+			}
+			r.notify()
+		}
+	})
+	if listenErr != nil {
+		return listenErr
+	}
+	return rawErr
+}
@@ -0,0 +1,180 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.io/xhkzeroone/goframex/pkg/database/gormx"
+	"gorm.io/gorm"
+)
+
+// DefaultTable là bảng outbox mặc định
+const DefaultTable = "outbox_events"
+
+// ErrInvalidEvent trả về khi event thiếu loại hoặc aggregate
+var ErrInvalidEvent = errors.New("outbox: event type and aggregate id are required")
+
+// Event là sự kiện cần publish, được ghi vào bảng outbox cùng transaction với dữ liệu nghiệp vụ
+type Event struct {
+	// AggregateType, AggregateID xác định thứ tự: các event cùng aggregate được publish lần lượt
+	AggregateType string
+	AggregateID   string
+	Type          string
+	// Payload được mã hóa JSON, có thể là json.RawMessage
+	Payload any
+	Headers map[string]string
+}
+
+// Message là event đã lưu trong outbox, được chuyển cho Sink
+type Message struct {
+	ID            uint64            `json:"id"`
+	AggregateType string            `json:"aggregateType"`
+	AggregateID   string            `json:"aggregateId"`
+	Type          string            `json:"type"`
+	Payload       json.RawMessage   `json:"payload"`
+	Headers       map[string]string `json:"headers,omitempty"`
+	CreatedAt     time.Time         `json:"createdAt"`
+	Attempts      int               `json:"attempts"`
+}
+
+// record là một dòng trong bảng outbox
+type record struct {
+	ID            uint64            `gorm:"column:id;primaryKey;autoIncrement"`
+	AggregateType string            `gorm:"column:aggregate_type;size:128;not null;index:idx_outbox_aggregate,priority:1"`
+	AggregateID   string            `gorm:"column:aggregate_id;size:128;not null;index:idx_outbox_aggregate,priority:2"`
+	EventType     string            `gorm:"column:event_type;size:128;not null"`
+	Payload       string            `gorm:"column:payload;type:text;not null"`
+	Headers       map[string]string `gorm:"column:headers;type:text;serializer:json"`
+	CreatedAt     time.Time         `gorm:"column:created_at;not null"`
+	Attempts      int               `gorm:"column:attempts;not null;default:0"`
+	NextAttemptAt time.Time         `gorm:"column:next_attempt_at;not null;index"`
+	LastError     string            `gorm:"column:last_error;type:text"`
+	PublishedAt   *time.Time        `gorm:"column:published_at;index"`
+	FailedAt      *time.Time        `gorm:"column:failed_at"`
+}
+
+func (r *record) message() *Message {
+	return &Message{
+		ID:            r.ID,
+		AggregateType: r.AggregateType,
+		AggregateID:   r.AggregateID,
+		Type:          r.EventType,
+		Payload:       json.RawMessage(r.Payload),
+		Headers:       r.Headers,
+		CreatedAt:     r.CreatedAt,
+		Attempts:      r.Attempts,
+	}
+}
+
+type Option func(o *Outbox)
+
+// WithTable đổi tên bảng outbox (mặc định outbox_events)
+func WithTable(table string) Option {
+	return func(o *Outbox) {
+		o.table = table
+	}
+}
+
+// WithNotifyChannel đổi kênh NOTIFY trên Postgres (mặc định trùng tên bảng), chuỗi rỗng để tắt
+func WithNotifyChannel(channel string) Option {
+	return func(o *Outbox) {
+		o.channel = channel
+	}
+}
+
+// Outbox ghi event vào bảng outbox qua DataSource.
+// Publish dùng transaction trong ctx (gormx.Transactional) nên event chỉ tồn tại khi dữ liệu nghiệp vụ được commit
+type Outbox struct {
+	ds      *gormx.DataSource
+	table   string
+	channel string
+}
+
+func New(ds *gormx.DataSource, opts ...Option) *Outbox {
+	o := &Outbox{ds: ds, table: DefaultTable}
+	if ds.Dialector.Name() == gormx.DriverPostgres {
+		o.channel = DefaultTable
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if ds.Dialector.Name() != gormx.DriverPostgres {
+		o.channel = ""
+	}
+	return o
+}
+
+// AutoMigrate tạo bảng outbox, dùng khi không quản lý schema bằng migration
+func (o *Outbox) AutoMigrate(ctx context.Context) error {
+	return o.db(ctx).AutoMigrate(&record{})
+}
+
+// Publish ghi các event vào outbox. Gọi trong gormx.Transactional để ghi cùng transaction với dữ liệu nghiệp vụ,
+// ngoài transaction event được ghi ngay
+func (o *Outbox) Publish(ctx context.Context, events ...Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	now := time.Now().UTC()
+	records := make([]record, 0, len(events))
+	for _, e := range events {
+		if e.Type == "" || e.AggregateID == "" {
+			return ErrInvalidEvent
+		}
+		payload, err := encodePayload(e.Payload)
+		if err != nil {
+			return fmt.Errorf("outbox: encode payload of %s: %w", e.Type, err)
+		}
+		headers := e.Headers
+		if tenant, ok := gormx.TenantFromContext(ctx); ok {
+			headers = make(map[string]string, len(e.Headers)+1)
+			for k, v := range e.Headers {
+				headers[k] = v
+			}
+			if _, ok := headers["tenant"]; !ok {
+				headers["tenant"] = tenant
+			}
+		}
+		records = append(records, record{
+			AggregateType: e.AggregateType,
+			AggregateID:   e.AggregateID,
+			EventType:     e.Type,
+			Payload:       payload,
+			Headers:       headers,
+			CreatedAt:     now,
+			NextAttemptAt: now,
+		})
+	}
+
+	db := o.ds.Writer(gormx.WithoutTenant(ctx))
+	if err := db.Table(o.table).Create(&records).Error; err != nil {
+		return err
+	}
+	if o.channel != "" {
+		// Postgres chỉ gửi NOTIFY khi transaction commit nên relay không đọc được event chưa commit
+		return db.Exec("SELECT pg_notify(?, '')", o.channel).Error
+	}
+	return nil
+}
+
+// db trả về *gorm.DB trên primary cho bảng outbox, bỏ qua lọc tenant
+func (o *Outbox) db(ctx context.Context) *gorm.DB {
+	return o.ds.DB.WithContext(gormx.WithoutTenant(ctx)).Table(o.table)
+}
+
+func encodePayload(payload any) (string, error) {
+	switch p := payload.(type) {
+	case nil:
+		return "null", nil
+	case json.RawMessage:
+		return string(p), nil
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
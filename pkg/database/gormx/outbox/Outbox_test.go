@@ -0,0 +1,84 @@
+package outbox_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.io/xhkzeroone/goframex/pkg/database/gormx"
+	"github.io/xhkzeroone/goframex/pkg/database/gormx/gormxtest"
+	"github.io/xhkzeroone/goframex/pkg/database/gormx/outbox"
+)
+
+// row đọc trạng thái một event trong bảng outbox
+type row struct {
+	ID            uint64
+	AggregateID   string
+	EventType     string
+	Payload       string
+	Headers       string
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	PublishedAt   *time.Time
+	FailedAt      *time.Time
+}
+
+func newOutbox(t *testing.T) (*gormx.DataSource, *outbox.Outbox) {
+	t.Helper()
+	ds := gormxtest.New(t)
+	o := outbox.New(ds)
+	if err := o.AutoMigrate(context.Background()); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	return ds, o
+}
+
+func rows(t *testing.T, ds *gormx.DataSource) []row {
+	t.Helper()
+	var out []row
+	if err := ds.DB.Table(outbox.DefaultTable).Order("id").Find(&out).Error; err != nil {
+		t.Fatalf("read outbox: %v", err)
+	}
+	return out
+}
+
+func TestPublishJoinsTransaction(t *testing.T) {
+	ds, o := newOutbox(t)
+	ctx := gormx.WithTenant(context.Background(), "acme")
+	event := outbox.Event{AggregateType: "order", AggregateID: "1", Type: "order.created", Payload: map[string]int{"total": 30}}
+
+	rollback := errors.New("rollback")
+	err := gormx.Transactional(ctx, ds, func(ctx context.Context) error {
+		if err := o.Publish(ctx, event); err != nil {
+			return err
+		}
+		return rollback
+	})
+	if !errors.Is(err, rollback) {
+		t.Fatalf("transactional err = %v", err)
+	}
+	if got := rows(t, ds); len(got) != 0 {
+		t.Fatalf("rolled back transaction left %d events", len(got))
+	}
+
+	if err := gormx.Transactional(ctx, ds, func(ctx context.Context) error {
+		return o.Publish(ctx, event)
+	}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	got := rows(t, ds)
+	if len(got) != 1 || got[0].EventType != "order.created" || got[0].Payload != `{"total":30}` {
+		t.Fatalf("events = %+v", got)
+	}
+	var headers map[string]string
+	if err := json.Unmarshal([]byte(got[0].Headers), &headers); err != nil || headers["tenant"] != "acme" {
+		t.Errorf("headers = %q, want tenant from ctx", got[0].Headers)
+	}
+
+	if err := o.Publish(ctx, outbox.Event{Type: "order.created"}); !errors.Is(err, outbox.ErrInvalidEvent) {
+		t.Errorf("event without aggregate err = %v, want ErrInvalidEvent", err)
+	}
+}
@@ -0,0 +1,309 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.io/xhkzeroone/goframex/pkg/database/gormx"
+	"github.io/xhkzeroone/goframex/pkg/logger/logrusx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RelayOption func(r *Relay)
+
+// WithPollInterval chu kỳ quét outbox (mặc định 1s). Trên Postgres relay còn được đánh thức bằng NOTIFY
+func WithPollInterval(interval time.Duration) RelayOption {
+	return func(r *Relay) {
+		r.pollInterval = interval
+	}
+}
+
+// WithBatchSize số event tối đa mỗi lần quét (mặc định 100)
+func WithBatchSize(size int) RelayOption {
+	return func(r *Relay) {
+		r.batchSize = size
+	}
+}
+
+// WithMaxAttempts số lần publish tối đa trước khi event bị đánh dấu failed (mặc định 10).
+// Event failed không còn chặn các event sau của cùng aggregate
+func WithMaxAttempts(n int) RelayOption {
+	return func(r *Relay) {
+		r.maxAttempts = n
+	}
+}
+
+// WithRetryBackoff thời gian chờ trước lần thử lại, tăng gấp đôi từ initial tới maxDelay (mặc định 1s - 5 phút)
+func WithRetryBackoff(initial, maxDelay time.Duration) RelayOption {
+	return func(r *Relay) {
+		r.retryInitial = initial
+		r.retryMax = maxDelay
+	}
+}
+
+// WithPublishTimeout thời gian tối đa cho mỗi lần gọi Sink (mặc định 10s)
+func WithPublishTimeout(timeout time.Duration) RelayOption {
+	return func(r *Relay) {
+		r.publishTimeout = timeout
+	}
+}
+
+// WithCleanup xóa event đã publish cũ hơn retention, chạy mỗi interval (mặc định giữ 7 ngày, dọn mỗi giờ).
+// retention <= 0 để tắt
+func WithCleanup(retention, interval time.Duration) RelayOption {
+	return func(r *Relay) {
+		r.retention = retention
+		r.cleanupInterval = interval
+	}
+}
+
+// Relay đọc event chưa publish từ outbox và chuyển cho Sink.
+// Event cùng aggregate được publish đúng thứ tự ghi: event sau chỉ được gửi khi event trước đã publish (hoặc failed).
+// Nhiều relay có thể chạy song song trên Postgres/MySQL nhờ FOR UPDATE SKIP LOCKED khi claim.
+// Bảng outbox cần được tạo trước (migration hoặc Outbox.AutoMigrate)
+type Relay struct {
+	outbox *Outbox
+	sink   Sink
+
+	pollInterval    time.Duration
+	batchSize       int
+	maxAttempts     int
+	retryInitial    time.Duration
+	retryMax        time.Duration
+	publishTimeout  time.Duration
+	retention       time.Duration
+	cleanupInterval time.Duration
+
+	wake chan struct{}
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewRelay(o *Outbox, sink Sink, opts ...RelayOption) *Relay {
+	r := &Relay{
+		outbox:          o,
+		sink:            sink,
+		pollInterval:    time.Second,
+		batchSize:       100,
+		maxAttempts:     10,
+		retryInitial:    time.Second,
+		retryMax:        5 * time.Minute,
+		publishTimeout:  10 * time.Second,
+		retention:       7 * 24 * time.Hour,
+		cleanupInterval: time.Hour,
+		wake:            make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.pollInterval <= 0 {
+		r.pollInterval = time.Second
+	}
+	if r.batchSize <= 0 {
+		r.batchSize = 100
+	}
+	return r
+}
+
+// Start chạy relay trong nền cho tới khi Stop được gọi
+func (r *Relay) Start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cancel != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel, r.done = cancel, make(chan struct{})
+	go func(done chan struct{}) {
+		defer close(done)
+		r.Run(ctx)
+	}(r.done)
+}
+
+// Stop dừng relay và chờ lần publish đang chạy kết thúc hoặc ctx hết hạn
+func (r *Relay) Stop(ctx context.Context) error {
+	r.mu.Lock()
+	cancel, done := r.cancel, r.done
+	r.cancel, r.done = nil, nil
+	r.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Run chạy vòng lặp relay, block cho tới khi ctx bị hủy
+func (r *Relay) Run(ctx context.Context) {
+	if r.outbox.channel != "" {
+		go r.listen(ctx)
+	}
+
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+	var cleanup <-chan time.Time
+	if r.retention > 0 && r.cleanupInterval > 0 {
+		cleanupTicker := time.NewTicker(r.cleanupInterval)
+		defer cleanupTicker.Stop()
+		cleanup = cleanupTicker.C
+	}
+
+	for {
+		n, err := r.RunOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			logEntry(ctx).Warnf("outbox relay: %v", err)
+		}
+		// Còn event tồn đọng thì quét tiếp ngay, cleanup tới hạn vẫn được chạy xen giữa
+		if err == nil && n >= r.batchSize && ctx.Err() == nil {
+			select {
+			case <-cleanup:
+				r.runCleanup(ctx)
+			default:
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-r.wake:
+		case <-cleanup:
+			r.runCleanup(ctx)
+		}
+	}
+}
+
+func (r *Relay) runCleanup(ctx context.Context) {
+	if deleted, err := r.Cleanup(ctx); err != nil {
+		logEntry(ctx).Warnf("outbox cleanup: %v", err)
+	} else if deleted > 0 {
+		logEntry(ctx).Infof("outbox cleanup: deleted %d published events", deleted)
+	}
+}
+
+// RunOnce publish một lượt event đang chờ, trả về số event đã xử lý (cả thành công lẫn lỗi).
+// Event được claim trong một transaction ngắn rồi mới gửi cho Sink, Sink chậm không giữ kết nối hay khóa dòng
+func (r *Relay) RunOnce(ctx context.Context) (int, error) {
+	records, err := r.claim(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for i := range records {
+		// Relay đang dừng: các event còn lại được gửi lại khi hết hạn claim
+		if ctx.Err() != nil {
+			return i, ctx.Err()
+		}
+		if err := r.publish(ctx, &records[i]); err != nil {
+			return i, err
+		}
+	}
+	return len(records), nil
+}
+
+// claim chọn các event tới hạn và dời next_attempt_at ra sau thời gian gửi dự kiến của cả lượt,
+// relay khác sẽ bỏ qua chúng. Relay dừng giữa chừng thì event được gửi lại khi hết hạn claim
+func (r *Relay) claim(ctx context.Context) ([]record, error) {
+	o := r.outbox
+	var records []record
+	err := o.db(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UTC()
+		pending := tx.Session(&gorm.Session{NewDB: true}).Table(o.table + " AS p").Select("1").
+			Where("p.aggregate_type = o.aggregate_type AND p.aggregate_id = o.aggregate_id").
+			Where("p.published_at IS NULL AND p.failed_at IS NULL AND p.id < o.id")
+
+		q := tx.Session(&gorm.Session{NewDB: true}).Table(o.table+" AS o").
+			Where("o.published_at IS NULL AND o.failed_at IS NULL AND o.next_attempt_at <= ?", now).
+			Where("NOT EXISTS (?)", pending).
+			Order("o.id").
+			Limit(r.batchSize)
+		if name := tx.Dialector.Name(); name == gormx.DriverPostgres || name == gormx.DriverMySQL {
+			q = q.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+		if err := q.Find(&records).Error; err != nil || len(records) == 0 {
+			return err
+		}
+
+		ids := make([]uint64, len(records))
+		for i := range records {
+			ids[i] = records[i].ID
+		}
+		lease := r.publishTimeout * time.Duration(len(records)+1)
+		return tx.Session(&gorm.Session{NewDB: true}).Table(o.table).
+			Where("id IN ?", ids).Update("next_attempt_at", now.Add(lease)).Error
+	})
+	return records, err
+}
+
+// publish gửi một event rồi cập nhật trạng thái ngoài transaction, chỉ trả lỗi khi không cập nhật được outbox
+func (r *Relay) publish(ctx context.Context, rec *record) error {
+	pubCtx, cancel := context.WithTimeout(ctx, r.publishTimeout)
+	sendErr := r.sink.Publish(pubCtx, rec.message())
+	cancel()
+
+	now := time.Now().UTC()
+	updates := map[string]any{"attempts": rec.Attempts + 1}
+	if sendErr == nil {
+		updates["published_at"] = now
+		updates["last_error"] = ""
+	} else {
+		updates["last_error"] = sendErr.Error()
+		updates["next_attempt_at"] = now.Add(r.backoff(rec.Attempts + 1))
+		if r.maxAttempts > 0 && rec.Attempts+1 >= r.maxAttempts {
+			updates["failed_at"] = now
+			logEntry(ctx).Errorf("outbox event %d (%s) failed after %d attempts: %v", rec.ID, rec.EventType, rec.Attempts+1, sendErr)
+		} else {
+			logEntry(ctx).Warnf("outbox event %d (%s) publish failed, will retry: %v", rec.ID, rec.EventType, sendErr)
+		}
+	}
+	// Event đã gửi xong thì vẫn ghi nhận kể cả khi relay đang Stop
+	return r.outbox.db(context.WithoutCancel(ctx)).
+		Where("id = ? AND published_at IS NULL", rec.ID).Updates(updates).Error
+}
+
+// backoff trả về thời gian chờ sau lần thử thứ attempts: retryInitial * 2^(attempts-1), tối đa retryMax
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := r.retryInitial
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if r.retryMax > 0 && delay >= r.retryMax {
+			return r.retryMax
+		}
+	}
+	return delay
+}
+
+// Cleanup xóa event đã publish cũ hơn retention, event failed được giữ lại để kiểm tra
+func (r *Relay) Cleanup(ctx context.Context) (int64, error) {
+	if r.retention <= 0 {
+		return 0, nil
+	}
+	cutoff := time.Now().UTC().Add(-r.retention)
+	result := r.outbox.db(ctx).Where("published_at IS NOT NULL AND published_at < ?", cutoff).Delete(&record{})
+	return result.RowsAffected, result.Error
+}
+
+// notify đánh thức vòng lặp relay, không block nếu đã có tín hiệu đang chờ
+func (r *Relay) notify() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+func logEntry(ctx context.Context) *logrus.Entry {
+	if logrusx.Log == nil {
+		return logrus.NewEntry(logrus.StandardLogger())
+	}
+	return logrusx.WithContext(ctx)
+}
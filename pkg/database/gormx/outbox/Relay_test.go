@@ -0,0 +1,202 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.io/xhkzeroone/goframex/pkg/database/gormx/outbox"
+)
+
+// recordingSink ghi lại event đã nhận, fail trả lỗi cho event có Type tương ứng
+type recordingSink struct {
+	mu   sync.Mutex
+	got  []string
+	fail map[string]bool
+}
+
+func (s *recordingSink) Publish(_ context.Context, msg *outbox.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.got = append(s.got, msg.Type)
+	if s.fail[msg.Type] {
+		return errors.New("broker unavailable")
+	}
+	return nil
+}
+
+func (s *recordingSink) take() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	got := s.got
+	s.got = nil
+	return got
+}
+
+func publish(t *testing.T, o *outbox.Outbox, events ...outbox.Event) {
+	t.Helper()
+	if err := o.Publish(context.Background(), events...); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+}
+
+func runOnce(t *testing.T, r *outbox.Relay, want int) {
+	t.Helper()
+	if n, err := r.RunOnce(context.Background()); err != nil || n != want {
+		t.Fatalf("RunOnce = %d, %v; want %d", n, err, want)
+	}
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRelayKeepsAggregateOrder(t *testing.T) {
+	ds, o := newOutbox(t)
+	sink := &recordingSink{fail: map[string]bool{"a1": true}}
+	r := outbox.NewRelay(o, sink, outbox.WithRetryBackoff(time.Hour, time.Hour))
+	publish(t, o,
+		outbox.Event{AggregateType: "order", AggregateID: "a", Type: "a1"},
+		outbox.Event{AggregateType: "order", AggregateID: "a", Type: "a2"},
+		outbox.Event{AggregateType: "order", AggregateID: "b", Type: "b1"},
+	)
+
+	// a2 chờ a1, event của aggregate khác không bị chặn
+	runOnce(t, r, 2)
+	if got := sink.take(); !equal(got, []string{"a1", "b1"}) {
+		t.Fatalf("first pass published %v, want [a1 b1]", got)
+	}
+	// a1 đang chờ backoff nên a2 vẫn bị giữ
+	runOnce(t, r, 0)
+
+	sink.fail = nil
+	if err := ds.DB.Table(outbox.DefaultTable).Where("event_type = ?", "a1").
+		Update("next_attempt_at", time.Now().UTC().Add(-time.Second)).Error; err != nil {
+		t.Fatal(err)
+	}
+	runOnce(t, r, 1)
+	runOnce(t, r, 1)
+	if got := sink.take(); !equal(got, []string{"a1", "a2"}) {
+		t.Fatalf("retry published %v, want [a1 a2]", got)
+	}
+	for _, e := range rows(t, ds) {
+		if e.PublishedAt == nil || e.LastError != "" {
+			t.Errorf("event %s not published: %+v", e.EventType, e)
+		}
+	}
+}
+
+func TestRelayRetryBackoff(t *testing.T) {
+	ds, o := newOutbox(t)
+	sink := &recordingSink{fail: map[string]bool{"a1": true}}
+	r := outbox.NewRelay(o, sink, outbox.WithMaxAttempts(3), outbox.WithRetryBackoff(time.Minute, 3*time.Minute))
+	publish(t, o,
+		outbox.Event{AggregateType: "order", AggregateID: "a", Type: "a1"},
+		outbox.Event{AggregateType: "order", AggregateID: "a", Type: "a2"},
+	)
+
+	// Độ trễ tăng gấp đôi từ 1 phút và bị chặn ở 3 phút
+	for attempt, delay := range []time.Duration{time.Minute, 2 * time.Minute, 3 * time.Minute} {
+		before := time.Now().UTC()
+		runOnce(t, r, 1)
+		after := time.Now().UTC()
+		e := rows(t, ds)[0]
+		if e.Attempts != attempt+1 || e.LastError != "broker unavailable" {
+			t.Fatalf("attempt %d: %+v", attempt+1, e)
+		}
+		if e.NextAttemptAt.Before(before.Add(delay-time.Second)) || e.NextAttemptAt.After(after.Add(delay+time.Second)) {
+			t.Errorf("attempt %d: next attempt in %v, want %v", attempt+1, e.NextAttemptAt.Sub(before), delay)
+		}
+		if err := ds.DB.Table(outbox.DefaultTable).Where("id = ?", e.ID).
+			Update("next_attempt_at", before.Add(-time.Second)).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Hết số lần thử: a1 failed và không còn chặn a2
+	if e := rows(t, ds)[0]; e.FailedAt == nil {
+		t.Fatalf("a1 not failed after max attempts: %+v", e)
+	}
+	sink.take()
+	runOnce(t, r, 1)
+	if got := sink.take(); !equal(got, []string{"a2"}) {
+		t.Fatalf("after failure published %v, want [a2]", got)
+	}
+}
+
+func TestRelayCleanup(t *testing.T) {
+	ds, o := newOutbox(t)
+	sink := &recordingSink{fail: map[string]bool{"failed": true}}
+	r := outbox.NewRelay(o, sink, outbox.WithMaxAttempts(1))
+	publish(t, o,
+		outbox.Event{AggregateType: "order", AggregateID: "a", Type: "old"},
+		outbox.Event{AggregateType: "order", AggregateID: "b", Type: "recent"},
+		outbox.Event{AggregateType: "order", AggregateID: "c", Type: "failed"},
+	)
+	runOnce(t, r, 3)
+	if err := ds.DB.Table(outbox.DefaultTable).Where("event_type IN ?", []string{"old", "failed"}).
+		Update("published_at", time.Now().UTC().Add(-8*24*time.Hour)).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := ds.DB.Table(outbox.DefaultTable).Where("event_type = ?", "failed").Update("published_at", nil).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	deleted, err := r.Cleanup(context.Background())
+	if err != nil || deleted != 1 {
+		t.Fatalf("cleanup = %d, %v; want 1", deleted, err)
+	}
+	var left []string
+	for _, e := range rows(t, ds) {
+		left = append(left, e.EventType)
+	}
+	if !equal(left, []string{"recent", "failed"}) {
+		t.Errorf("events left = %v, want [recent failed]", left)
+	}
+
+	off := outbox.NewRelay(o, sink, outbox.WithCleanup(0, time.Hour))
+	if deleted, err := off.Cleanup(context.Background()); err != nil || deleted != 0 {
+		t.Errorf("disabled cleanup = %d, %v", deleted, err)
+	}
+}
+
+func TestRelayCleansUpWhileDraining(t *testing.T) {
+	ds, o := newOutbox(t)
+	var sent atomic.Int64
+	// Mỗi event được gửi lại sinh thêm một event nên lượt quét luôn đầy batch
+	var sink outbox.SinkFunc = func(ctx context.Context, msg *outbox.Message) error {
+		sent.Add(1)
+		return o.Publish(ctx, outbox.Event{AggregateType: "order", AggregateID: msg.Type + "x", Type: msg.Type + "x"})
+	}
+	r := outbox.NewRelay(o, sink,
+		outbox.WithBatchSize(1),
+		outbox.WithPollInterval(time.Hour),
+		outbox.WithCleanup(time.Nanosecond, 5*time.Millisecond),
+	)
+	publish(t, o, outbox.Event{AggregateType: "order", AggregateID: "a", Type: "a"})
+
+	r.Start()
+	time.Sleep(100 * time.Millisecond)
+	if err := r.Stop(context.Background()); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+
+	var published int64
+	if err := ds.DB.Table(outbox.DefaultTable).Where("published_at IS NOT NULL").Count(&published).Error; err != nil {
+		t.Fatal(err)
+	}
+	if n := sent.Load(); n < 2 || published >= n {
+		t.Errorf("sent %d events, %d published rows left; cleanup did not run while draining", n, published)
+	}
+}
@@ -0,0 +1,77 @@
+package outbox
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.io/xhkzeroone/goframex/pkg/cache/redisx"
+	"github.io/xhkzeroone/goframex/pkg/http/restyx"
+)
+
+// Sink là nơi relay publish event tới. Trả về lỗi để relay thử lại sau,
+// vì vậy phía nhận cần idempotent theo Message.ID
+type Sink interface {
+	Publish(ctx context.Context, msg *Message) error
+}
+
+// SinkFunc cho phép dùng function làm Sink
+type SinkFunc func(ctx context.Context, msg *Message) error
+
+func (f SinkFunc) Publish(ctx context.Context, msg *Message) error {
+	return f(ctx, msg)
+}
+
+// RedisStreamSink publish event vào Redis Stream bằng XADD.
+// stream nhận message và trả về tên stream, nil thì dùng "events.<aggregateType>"
+func RedisStreamSink(client *redisx.Redis, stream func(msg *Message) string) Sink {
+	if stream == nil {
+		stream = func(msg *Message) string { return "events." + msg.AggregateType }
+	}
+	return SinkFunc(func(ctx context.Context, msg *Message) error {
+		values := map[string]any{
+			"id":             strconv.FormatUint(msg.ID, 10),
+			"type":           msg.Type,
+			"aggregate_type": msg.AggregateType,
+			"aggregate_id":   msg.AggregateID,
+			"payload":        string(msg.Payload),
+			"created_at":     msg.CreatedAt.Format(time.RFC3339Nano),
+		}
+		for k, v := range msg.Headers {
+			values["header."+k] = v
+		}
+		return client.XAdd(ctx, &redis.XAddArgs{Stream: stream(msg), Values: values}).Err()
+	})
+}
+
+// WebhookSink POST message dạng JSON tới path của client, header của event được gửi kèm
+// cùng X-Event-Id, X-Event-Type để phía nhận khử trùng lặp
+func WebhookSink(client *restyx.Client, path string) Sink {
+	return SinkFunc(func(ctx context.Context, msg *Message) error {
+		builder := restyx.NewRequest().
+			WithContext(ctx).
+			MethodPost().
+			WithPath(path).
+			AddHeader("X-Event-Id", strconv.FormatUint(msg.ID, 10)).
+			AddHeader("X-Event-Type", msg.Type)
+		for k, v := range msg.Headers {
+			builder.AddHeader(k, v)
+		}
+		return client.Exchange(builder.WithBody(msg).Build(), nil)
+	})
+}
+
+// LogSink ghi event ra log qua logrusx, dùng khi phát triển hoặc chưa có message broker
+func LogSink() Sink {
+	return SinkFunc(func(ctx context.Context, msg *Message) error {
+		logEntry(ctx).WithFields(logrus.Fields{
+			"event_id":       msg.ID,
+			"event_type":     msg.Type,
+			"aggregate_type": msg.AggregateType,
+			"aggregate_id":   msg.AggregateID,
+		}).Infof("outbox event: %s", string(msg.Payload))
+		return nil
+	})
+}
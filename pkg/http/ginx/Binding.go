@@ -0,0 +1,435 @@
+package ginx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Các hàm Bind điền dữ liệu vào struct theo tag rồi validate theo tag binding (go-playground/validator):
+//
+//	json    body JSON
+//	form    query string (BindQuery) hoặc body form (BindForm)
+//	uri     path variable, ví dụ /users/:id
+//	header  header, không phân biệt hoa thường
+//
+// Lỗi validate là *ValidationError, lỗi đọc dữ liệu là *HTTPError 400; handler chỉ cần trả về lỗi là được render 400
+
+// FieldError mô tả một field không hợp lệ. Field là đường dẫn theo tên trong tag, ví dụ "items[0].email"
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// ValidationError chứa danh sách field không hợp lệ, được render thành 400 với details là Fields
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Field + " " + f.Message
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// Bind điền struct từ body (theo Content-Type), query, header rồi path variable (nguồn sau ghi đè nguồn trước)
+// và validate một lần sau cùng. Query, header, path chỉ được đọc khi struct có field mang tag tương ứng.
+// Giá trị default= chỉ được dùng khi không nguồn nào gửi field đó. File upload cần dùng BindForm
+func (c *Context) Bind(obj any) error {
+	sources := []struct {
+		tag    string
+		values map[string][]string
+	}{
+		{"form", c.Request.URL.Query()},
+		{"header", c.Request.Header},
+		{"uri", c.uriValues()},
+	}
+	// Gán default trước body, các nguồn sau body chỉ ghi đè field mà nguồn đó có gửi
+	for _, src := range sources {
+		if len(tagNames(reflect.TypeOf(obj), src.tag)) == 0 {
+			continue
+		}
+		if err := mapValues(obj, nil, src.tag); err != nil {
+			return err
+		}
+	}
+	if err := c.bindBody(obj); err != nil {
+		return err
+	}
+	for _, src := range sources {
+		names := tagNames(reflect.TypeOf(obj), src.tag)
+		if len(names) == 0 {
+			continue
+		}
+		values := pick(src.values, names, src.tag == "header")
+		restore := keepDefaulted(obj, src.tag, values)
+		err := mapValues(obj, values, src.tag)
+		restore()
+		if err != nil {
+			return err
+		}
+	}
+	return validateStruct(obj)
+}
+
+// BindJSON đọc body JSON, giữ nguyên các field không có trong body
+func (c *Context) BindJSON(obj any) error {
+	if err := decodeJSON(c.bodyBytes, obj); err != nil {
+		return err
+	}
+	return validateStruct(obj)
+}
+
+// BindQuery đọc query string theo tag form
+func (c *Context) BindQuery(obj any) error {
+	if err := mapValues(obj, c.Request.URL.Query(), "form"); err != nil {
+		return err
+	}
+	return validateStruct(obj)
+}
+
+// BindURI đọc path variable theo tag uri
+func (c *Context) BindURI(obj any) error {
+	if err := mapValues(obj, c.uriValues(), "uri"); err != nil {
+		return err
+	}
+	return validateStruct(obj)
+}
+
+// BindHeader đọc header theo tag header
+func (c *Context) BindHeader(obj any) error {
+	names := tagNames(reflect.TypeOf(obj), "header")
+	if err := mapValues(obj, pick(c.Request.Header, names, true), "header"); err != nil {
+		return err
+	}
+	return validateStruct(obj)
+}
+
+// BindForm đọc body application/x-www-form-urlencoded hoặc multipart/form-data theo tag form,
+// field kiểu *multipart.FileHeader nhận file upload
+func (c *Context) BindForm(obj any) error {
+	c.Request.Body = io.NopCloser(bytes.NewReader(c.bodyBytes))
+	b := binding.Form
+	if c.mediaType() == binding.MIMEMultipartPOSTForm {
+		b = binding.FormMultipart
+	}
+	// binding của gin validate luôn, đổi lỗi của validator sang ValidationError
+	if err := b.Bind(c.Request, obj); err != nil {
+		var fieldErrs validator.ValidationErrors
+		if errors.As(err, &fieldErrs) {
+			return newValidationError(obj, fieldErrs)
+		}
+		return NewHTTPError(http.StatusBadRequest, "invalid form data").WithCause(err)
+	}
+	return nil
+}
+
+// bindBody chọn cách đọc body theo Content-Type, body rỗng được bỏ qua
+func (c *Context) bindBody(obj any) error {
+	if len(c.bodyBytes) == 0 {
+		return nil
+	}
+	switch c.mediaType() {
+	case binding.MIMEPOSTForm:
+		values, err := url.ParseQuery(string(c.bodyBytes))
+		if err != nil {
+			return NewHTTPError(http.StatusBadRequest, "invalid form data").WithCause(err)
+		}
+		return mapValues(obj, values, "form")
+	case binding.MIMEMultipartPOSTForm:
+		c.Request.Body = io.NopCloser(bytes.NewReader(c.bodyBytes))
+		if err := c.Request.ParseMultipartForm(32 << 20); err != nil {
+			return NewHTTPError(http.StatusBadRequest, "invalid form data").WithCause(err)
+		}
+		return mapValues(obj, c.Request.MultipartForm.Value, "form")
+	default:
+		return decodeJSON(c.bodyBytes, obj)
+	}
+}
+
+func (c *Context) mediaType() string {
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	return mediaType
+}
+
+func (c *Context) uriValues() map[string][]string {
+	values := make(map[string][]string, len(c.Params))
+	for _, p := range c.Params {
+		values[p.Key] = []string{p.Value}
+	}
+	return values
+}
+
+// decodeJSON đổi lỗi sai kiểu thành ValidationError để client biết field nào sai
+func decodeJSON(body []byte, obj any) error {
+	err := json.Unmarshal(body, obj)
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		return &ValidationError{Fields: []FieldError{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Param:   typeErr.Type.String(),
+			Message: "must be of type " + typeErr.Type.String(),
+		}}}
+	default:
+		return NewHTTPError(http.StatusBadRequest, "invalid JSON body").WithCause(err)
+	}
+}
+
+func mapValues(obj any, values map[string][]string, tag string) error {
+	if err := binding.MapFormWithTag(obj, values, tag); err != nil {
+		return NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid %s parameter", tagSource[tag])).WithCause(err)
+	}
+	return nil
+}
+
+var tagSource = map[string]string{"form": "query", "uri": "path", "header": "header"}
+
+// pick chỉ giữ các key được khai báo trong tag; header được tra không phân biệt hoa thường
+func pick(values map[string][]string, names []string, header bool) map[string][]string {
+	out := make(map[string][]string, len(names))
+	for _, name := range names {
+		if header {
+			if v := http.Header(values).Values(name); len(v) > 0 {
+				out[name] = v
+			}
+		} else if v, ok := values[name]; ok {
+			out[name] = v
+		}
+	}
+	return out
+}
+
+// keepDefaulted lưu giá trị hiện tại của các field có default= mà values không gửi và trả về hàm khôi phục,
+// vì MapFormWithTag luôn gán default cho key vắng mặt và sẽ ghi đè giá trị đã đọc từ body
+func keepDefaulted(obj any, tag string, values map[string][]string) (restore func()) {
+	type saved struct{ field, value reflect.Value }
+	var fields []saved
+	var walk func(v reflect.Value)
+	walk = func(v reflect.Value) {
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name, opts, _ := strings.Cut(f.Tag.Get(tag), ",")
+			if name == "" || name == "-" {
+				walk(v.Field(i))
+				continue
+			}
+			if _, ok := values[name]; !ok && strings.Contains(opts, "default=") {
+				value := reflect.New(f.Type).Elem()
+				value.Set(v.Field(i))
+				fields = append(fields, saved{v.Field(i), value})
+			}
+		}
+	}
+	walk(reflect.ValueOf(obj))
+	return func() {
+		for _, s := range fields {
+			s.field.Set(s.value)
+		}
+	}
+}
+
+type tagKey struct {
+	t   reflect.Type
+	tag string
+}
+
+var tagNamesCache sync.Map
+
+// tagNames liệt kê các tên khai báo bằng tag trong struct, kể cả struct lồng nhau
+func tagNames(t reflect.Type, tag string) []string {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+	key := tagKey{t, tag}
+	if v, ok := tagNamesCache.Load(key); ok {
+		return v.([]string)
+	}
+	var names []string
+	collectTagNames(t, tag, &names, map[reflect.Type]bool{})
+	tagNamesCache.Store(key, names)
+	return names
+}
+
+func collectTagNames(t reflect.Type, tag string, names *[]string, visiting map[reflect.Type]bool) {
+	if visiting[t] {
+		return
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if name, _, _ := strings.Cut(f.Tag.Get(tag), ","); name != "" && name != "-" {
+			*names = append(*names, name)
+			continue
+		}
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && f.IsExported() {
+			collectTagNames(ft, tag, names, visiting)
+		}
+	}
+}
+
+// validateStruct chạy validator của gin (tag binding)
+func validateStruct(obj any) error {
+	err := binding.Validator.ValidateStruct(obj)
+	if err == nil {
+		return nil
+	}
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return NewHTTPError(http.StatusBadRequest, err.Error()).WithCause(err)
+	}
+	return newValidationError(obj, fieldErrs)
+}
+
+func newValidationError(obj any, fieldErrs validator.ValidationErrors) *ValidationError {
+	fields := make([]FieldError, len(fieldErrs))
+	for i, fe := range fieldErrs {
+		fields[i] = FieldError{
+			Field:   fieldPath(reflect.TypeOf(obj), fe.StructNamespace()),
+			Rule:    fe.Tag(),
+			Param:   fe.Param(),
+			Message: fieldMessage(fe),
+		}
+	}
+	return &ValidationError{Fields: fields}
+}
+
+// fieldPath đổi "Request.Items[0].Email" thành "items[0].email" theo tên trong tag json, form, uri, header
+func fieldPath(t reflect.Type, namespace string) string {
+	parts := strings.Split(namespace, ".")
+	if len(parts) > 1 {
+		// phần đầu là tên kiểu gốc
+		parts = parts[1:]
+	}
+	for i, part := range parts {
+		name, index, _ := strings.Cut(part, "[")
+		if index != "" {
+			index = "[" + index
+		}
+		for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map) {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			continue
+		}
+		f, ok := t.FieldByName(name)
+		if !ok {
+			t = nil
+			continue
+		}
+		parts[i] = fieldName(f) + index
+		t = f.Type
+		if index != "" {
+			t = elemType(t)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+func elemType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		return t.Elem()
+	}
+	return t
+}
+
+func fieldName(f reflect.StructField) string {
+	for _, tag := range []string{"json", "form", "uri", "header"} {
+		if name, _, _ := strings.Cut(f.Tag.Get(tag), ","); name != "" && name != "-" {
+			return name
+		}
+	}
+	return f.Name
+}
+
+// fieldMessage là thông điệp tiếng Anh cho các rule thường dùng
+func fieldMessage(fe validator.FieldError) string {
+	param := fe.Param()
+	size := fe.Kind() == reflect.String || fe.Kind() == reflect.Slice || fe.Kind() == reflect.Map || fe.Kind() == reflect.Array
+	unit := ""
+	if size {
+		unit = " characters"
+		if fe.Kind() != reflect.String {
+			unit = " items"
+		}
+	}
+	switch fe.Tag() {
+	case "required", "required_if", "required_unless", "required_with", "required_without":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "url", "http_url":
+		return "must be a valid URL"
+	case "uuid", "uuid4":
+		return "must be a valid UUID"
+	case "oneof":
+		return "must be one of [" + param + "]"
+	case "len":
+		return "must have exactly " + param + unit
+	case "min":
+		if size {
+			return "must have at least " + param + unit
+		}
+		return "must be at least " + param
+	case "max":
+		if size {
+			return "must have at most " + param + unit
+		}
+		return "must be at most " + param
+	case "gt", "gte", "lt", "lte":
+		ops := map[string]string{"gt": "greater than", "gte": "greater than or equal to", "lt": "less than", "lte": "less than or equal to"}
+		return "must be " + ops[fe.Tag()] + " " + param
+	case "numeric", "number":
+		return "must be a number"
+	case "alphanum":
+		return "must contain only letters and digits"
+	case "datetime":
+		return "must be a datetime in format " + strconv.Quote(param)
+	}
+	if param != "" {
+		return fmt.Sprintf("failed the %s=%s rule", fe.Tag(), param)
+	}
+	return fmt.Sprintf("failed the %s rule", fe.Tag())
+}
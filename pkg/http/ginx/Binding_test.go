@@ -0,0 +1,188 @@
+package ginx
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type bindAddress struct {
+	City string `json:"city" binding:"required"`
+}
+
+type bindItem struct {
+	SKU   string `json:"sku" binding:"required"`
+	Email string `json:"email" binding:"omitempty,email"`
+}
+
+type bindOrder struct {
+	ID       string                 `uri:"id" binding:"required"`
+	Tenant   string                 `header:"X-Tenant" binding:"required"`
+	Page     int                    `form:"page,default=1" binding:"min=1"`
+	Note     string                 `binding:"max=3"`
+	Address  *bindAddress           `json:"address" binding:"required"`
+	Items    []bindItem             `json:"items" binding:"dive"`
+	Shipping map[string]bindAddress `json:"shipping" binding:"dive"`
+	Ignored  string                 `json:"-" binding:"max=1"`
+}
+
+func TestFieldPath(t *testing.T) {
+	typ := reflect.TypeOf(bindOrder{})
+	for _, tc := range []struct{ namespace, want string }{
+		{"bindOrder.ID", "id"},
+		{"bindOrder.Tenant", "X-Tenant"},
+		{"bindOrder.Page", "page"},
+		{"bindOrder.Note", "Note"},
+		{"bindOrder.Ignored", "Ignored"},
+		{"bindOrder.Address.City", "address.city"},
+		{"bindOrder.Items[2].Email", "items[2].email"},
+		{"bindOrder.Items[0]", "items[0]"},
+		{"bindOrder.Shipping[home].City", "shipping[home].city"},
+		{"bindOrder.Unknown.City", "Unknown.City"},
+		{"Single", "Single"},
+	} {
+		if got := fieldPath(typ, tc.namespace); got != tc.want {
+			t.Errorf("fieldPath(%q) = %q, want %q", tc.namespace, got, tc.want)
+		}
+	}
+}
+
+func TestBindReportsFieldPaths(t *testing.T) {
+	srv := New(&Config{Mode: "test"})
+	var bound bindOrder
+	srv.POST("/orders/:id", func(ctx *Context) error {
+		bound = bindOrder{}
+		if err := ctx.Bind(&bound); err != nil {
+			return err
+		}
+		ctx.JSON(http.StatusOK, map[string]string{"status": "ok"})
+		return nil
+	})
+	post := func(body string, header map[string]string) (int, ErrorBody) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/orders/42", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		var resp ErrorBody
+		_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+		return rec.Code, resp
+	}
+
+	code, _ := post(`{"address":{"city":"Hanoi"},"items":[{"sku":"a"}]}`, map[string]string{"x-tenant": "acme"})
+	if code != http.StatusOK {
+		t.Fatalf("valid request: code %d", code)
+	}
+	if bound.ID != "42" || bound.Tenant != "acme" || bound.Page != 1 {
+		t.Errorf("bound = %+v, want id, tenant and default page", bound)
+	}
+
+	code, resp := post(`{"address":{},"items":[{"sku":"a"},{"sku":"b","email":"nope"}],"shipping":{"home":{}}}`, nil)
+	if code != http.StatusBadRequest {
+		t.Fatalf("invalid request: code %d", code)
+	}
+	raw, _ := json.Marshal(resp.Details)
+	var fields []FieldError
+	_ = json.Unmarshal(raw, &fields)
+	got := make(map[string]string, len(fields))
+	for _, f := range fields {
+		got[f.Field] = f.Rule
+	}
+	want := map[string]string{
+		"X-Tenant":            "required",
+		"address.city":        "required",
+		"items[1].email":      "email",
+		"shipping[home].city": "required",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("fields = %v, want %v", got, want)
+	}
+
+	if code, _ := post(`{"items":"oops"}`, map[string]string{"x-tenant": "acme"}); code != http.StatusBadRequest {
+		t.Errorf("type mismatch: code %d", code)
+	}
+}
+
+func TestBindQueryDefaults(t *testing.T) {
+	type page struct {
+		Limit  int `form:"limit,default=10" binding:"min=1,max=100"`
+		Offset int `form:"offset,default=0" binding:"min=0"`
+	}
+	srv := New(&Config{Mode: "test"})
+	var bound page
+	srv.GET("/items", func(ctx *Context) error {
+		bound = page{}
+		if err := ctx.BindQuery(&bound); err != nil {
+			return err
+		}
+		ctx.JSON(http.StatusOK, map[string]string{"status": "ok"})
+		return nil
+	})
+	get := func(query string) int {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/items"+query, nil))
+		return rec.Code
+	}
+
+	if code := get(""); code != http.StatusOK || bound != (page{Limit: 10}) {
+		t.Errorf("no query: code %d, bound %+v", code, bound)
+	}
+	if code := get("?limit=25&offset=50"); code != http.StatusOK || bound != (page{Limit: 25, Offset: 50}) {
+		t.Errorf("explicit query: code %d, bound %+v", code, bound)
+	}
+	for _, query := range []string{"?limit=0", "?limit=101", "?offset=-1", "?limit=abc"} {
+		if code := get(query); code != http.StatusBadRequest {
+			t.Errorf("%s: code %d, want 400", query, code)
+		}
+	}
+}
+
+func TestBindBodyValuesWinOverDefaults(t *testing.T) {
+	type listRequest struct {
+		Limit  int    `json:"limit" form:"limit,default=10"`
+		Offset int    `json:"offset" form:"offset,default=5"`
+		Tenant string `json:"tenant" header:"X-Tenant,default=public"`
+	}
+	srv := New(&Config{Mode: "test"})
+	var bound listRequest
+	srv.POST("/items", func(ctx *Context) error {
+		bound = listRequest{}
+		if err := ctx.Bind(&bound); err != nil {
+			return err
+		}
+		ctx.JSON(http.StatusOK, map[string]string{"status": "ok"})
+		return nil
+	})
+
+	for _, tc := range []struct {
+		name, query, contentType, body string
+		header                         map[string]string
+		want                           listRequest
+	}{
+		{"no input", "", "", "", nil, listRequest{Limit: 10, Offset: 5, Tenant: "public"}},
+		{"empty json", "", "application/json", `{}`, nil, listRequest{Limit: 10, Offset: 5, Tenant: "public"}},
+		{"json body", "", "application/json", `{"limit":50,"tenant":"acme"}`, nil, listRequest{Limit: 50, Offset: 5, Tenant: "acme"}},
+		{"form body", "", "application/x-www-form-urlencoded", "limit=50", nil, listRequest{Limit: 50, Offset: 5, Tenant: "public"}},
+		{"query overrides body", "?limit=20", "application/json", `{"limit":50,"offset":1}`, nil, listRequest{Limit: 20, Offset: 1, Tenant: "public"}},
+		{"header overrides body", "", "application/json", `{"tenant":"acme"}`, map[string]string{"X-Tenant": "globex"}, listRequest{Limit: 10, Offset: 5, Tenant: "globex"}},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/items"+tc.query, strings.NewReader(tc.body))
+		if tc.contentType != "" {
+			req.Header.Set("Content-Type", tc.contentType)
+		}
+		for k, v := range tc.header {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK || bound != tc.want {
+			t.Errorf("%s: code %d, bound %+v, want %+v", tc.name, rec.Code, bound, tc.want)
+		}
+	}
+}
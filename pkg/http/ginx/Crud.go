@@ -0,0 +1,654 @@
+package ginx
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.io/xhkzeroone/goframex/pkg/database/gormx"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Operation là một route do CRUD mount
+type Operation int
+
+const (
+	OpList   Operation = iota // GET    /
+	OpGet                     // GET    /:id
+	OpCreate                  // POST   /
+	OpUpdate                  // PUT    /:id
+	OpPatch                   // PATCH  /:id
+	OpDelete                  // DELETE /:id
+)
+
+// Các query param dành riêng của route list, còn lại được hiểu là filter
+const (
+	queryPage     = "page"
+	queryPageSize = "page_size"
+	querySort     = "sort"
+)
+
+type CRUDOption func(o *crudOptions)
+
+type crudOptions struct {
+	filterable      map[string]bool
+	sortable        map[string]bool
+	writable        map[string]bool
+	defaultSort     []gormx.Order
+	defaultPageSize int
+	maxPageSize     int
+	operations      map[Operation]bool
+	// các hook generic, được kiểm tra kiểu khi gọi CRUD
+	idParser  any
+	request   any
+	response  any
+	validator any
+}
+
+// WithFilterable cho phép lọc theo các cột qua query string, ví dụ ?status=active&age[gte]=18.
+// Toán tử hỗ trợ: eq, ne, gt, gte, lt, lte, like (chứa chuỗi, % và _ vẫn là ký tự đại diện),
+// in (phân tách bằng dấu phẩy), null (true/false)
+func WithFilterable(columns ...string) CRUDOption {
+	return func(o *crudOptions) {
+		o.filterable = addSet(o.filterable, columns)
+	}
+}
+
+// WithSortable cho phép sắp xếp theo các cột qua ?sort=-created_at,name (dấu - là giảm dần)
+func WithSortable(columns ...string) CRUDOption {
+	return func(o *crudOptions) {
+		o.sortable = addSet(o.sortable, columns)
+	}
+}
+
+// WithWritable giới hạn các key JSON client được gửi khi create/update/patch, key khác trả về 400.
+// Không khai báo thì khi body được đọc thẳng vào entity (PATCH, create/update không có WithRequestDTO)
+// mọi key đều được nhận trừ khóa chính và các cột do gormx quản lý (version, created_*, updated_*,
+// deleted_at, tenant_id). Body form (WithRequestDTO) không bị kiểm tra, DTO quyết định field được ghi
+func WithWritable(fields ...string) CRUDOption {
+	return func(o *crudOptions) {
+		o.writable = addSet(o.writable, fields)
+	}
+}
+
+// WithDefaultSort thứ tự sắp xếp khi client không truyền sort (mặc định theo khóa chính)
+func WithDefaultSort(orders ...gormx.Order) CRUDOption {
+	return func(o *crudOptions) {
+		o.defaultSort = orders
+	}
+}
+
+// WithPageSize kích thước trang mặc định và tối đa (mặc định 20 và 100)
+func WithPageSize(defaultSize, maxSize int) CRUDOption {
+	return func(o *crudOptions) {
+		o.defaultPageSize, o.maxPageSize = defaultSize, maxSize
+	}
+}
+
+// WithOperations chỉ mount các route được liệt kê, ví dụ chỉ đọc: WithOperations(OpList, OpGet)
+func WithOperations(ops ...Operation) CRUDOption {
+	return func(o *crudOptions) {
+		o.operations = make(map[Operation]bool, len(ops))
+		for _, op := range ops {
+			o.operations[op] = true
+		}
+	}
+}
+
+// WithIDParser đọc ID từ path. Mặc định hỗ trợ string, số nguyên và kiểu implement encoding.TextUnmarshaler (uuid.UUID)
+func WithIDParser[ID any](parse func(raw string) (ID, error)) CRUDOption {
+	return func(o *crudOptions) {
+		o.idParser = parse
+	}
+}
+
+// WithRequestDTO bind request vào DTO D bằng Context.Bind (body, query, header, path) rồi map sang entity
+// khi create và update (PUT).
+// Với update, entity là bản ghi hiện có nên các field không được map vẫn giữ nguyên
+func WithRequestDTO[D, T any](mapTo func(dto *D, entity *T) error) CRUDOption {
+	return func(o *crudOptions) {
+		o.request = func(c *Context, entity *T) error {
+			dto := new(D)
+			if err := c.Bind(dto); err != nil {
+				return err
+			}
+			return mapTo(dto, entity)
+		}
+	}
+}
+
+// WithResponseDTO map entity sang DTO trước khi trả về cho client
+func WithResponseDTO[T, R any](mapTo func(entity *T) R) CRUDOption {
+	return func(o *crudOptions) {
+		o.response = func(entity *T) any { return mapTo(entity) }
+	}
+}
+
+// WithValidator kiểm tra entity trước khi ghi (sau khi map DTO hoặc merge PATCH).
+// Lỗi không phải HTTPError được trả về 400
+func WithValidator[T any](validate func(ctx context.Context, entity *T) error) CRUDOption {
+	return func(o *crudOptions) {
+		o.validator = validate
+	}
+}
+
+// CRUD mount các route REST cho entity T vào group:
+//
+//	GET    /     danh sách có filter, sort, phân trang (page, page_size)
+//	GET    /:id  chi tiết
+//	POST   /     tạo mới
+//	PUT    /:id  cập nhật qua request DTO
+//	PATCH  /:id  merge các key JSON được gửi vào entity hiện có
+//	DELETE /:id  xóa
+//
+// Lỗi được trả về theo ErrorBody: không tìm thấy 404, xung đột version 409, request sai 400.
+// Các hook sai kiểu so với T, ID sẽ panic khi mount
+func CRUD[T any, ID comparable](group *RouterGroup, repo gormx.IRepository[T, ID], opts ...CRUDOption) {
+	o := &crudOptions{defaultPageSize: 20, maxPageSize: 100}
+	for _, opt := range opts {
+		opt(o)
+	}
+	h := newCRUDHandler[T, ID](repo, o)
+
+	if o.enabled(OpList) {
+		group.GET("", h.list)
+	}
+	if o.enabled(OpGet) {
+		group.GET("/:id", h.get)
+	}
+	if o.enabled(OpCreate) {
+		group.POST("", h.create)
+	}
+	if o.enabled(OpUpdate) {
+		group.PUT("/:id", h.update)
+	}
+	if o.enabled(OpPatch) {
+		group.PATCH("/:id", h.patch)
+	}
+	if o.enabled(OpDelete) {
+		group.DELETE("/:id", h.delete)
+	}
+}
+
+func (o *crudOptions) enabled(op Operation) bool {
+	return o.operations == nil || o.operations[op]
+}
+
+type crudHandler[T any, ID comparable] struct {
+	repo     gormx.IRepository[T, ID]
+	opts     *crudOptions
+	sch      *schema.Schema
+	readOnly map[string]bool
+	parseID  func(raw string) (ID, error)
+	request  func(c *Context, entity *T) error
+	response func(entity *T) any
+	validate func(ctx context.Context, entity *T) error
+}
+
+var crudSchemaCache sync.Map
+
+// schemaProvider được gormx.Repository và CachedRepository implement
+type schemaProvider interface {
+	Schema() (*schema.Schema, error)
+}
+
+// crudSchema lấy schema từ repository để tên cột theo NamingStrategy của DataSource,
+// repository khác (mock...) dùng NamingStrategy mặc định
+func crudSchema[T any, ID comparable](repo gormx.IRepository[T, ID]) (*schema.Schema, error) {
+	if p, ok := repo.(schemaProvider); ok {
+		return p.Schema()
+	}
+	return schema.Parse(new(T), &crudSchemaCache, schema.NamingStrategy{})
+}
+
+func newCRUDHandler[T any, ID comparable](repo gormx.IRepository[T, ID], o *crudOptions) *crudHandler[T, ID] {
+	sch, err := crudSchema(repo)
+	if err != nil {
+		panic(fmt.Sprintf("ginx: CRUD cannot parse %T: %v", *new(T), err))
+	}
+	h := &crudHandler[T, ID]{
+		repo:     repo,
+		opts:     o,
+		sch:      sch,
+		readOnly: readOnlyKeys(sch),
+		parseID:  parseIDText[ID],
+		request:  decodeEntity[T],
+		response: func(entity *T) any { return entity },
+	}
+	hook(&h.parseID, o.idParser, "WithIDParser")
+	hook(&h.request, o.request, "WithRequestDTO")
+	hook(&h.response, o.response, "WithResponseDTO")
+	hook(&h.validate, o.validator, "WithValidator")
+	return h
+}
+
+// hook gán fn vào dst nếu đúng kiểu, sai kiểu là lỗi lập trình nên panic ngay khi mount
+func hook[F any](dst *F, fn any, name string) {
+	if fn == nil {
+		return
+	}
+	f, ok := fn.(F)
+	if !ok {
+		panic(fmt.Sprintf("ginx: CRUD %s hook has type %T, want %T", name, fn, *dst))
+	}
+	*dst = f
+}
+
+func (h *crudHandler[T, ID]) list(c *Context) error {
+	spec, page, pageSize, err := h.listQuery(c)
+	if err != nil {
+		return err
+	}
+	res, err := h.repo.Page(c.Request.Context(), spec, page, pageSize)
+	if err != nil {
+		return repositoryError(err)
+	}
+	items := make([]any, len(res.Items))
+	for i := range res.Items {
+		items[i] = h.response(&res.Items[i])
+	}
+	c.JSON(http.StatusOK, gormx.Page[any]{
+		Items:      items,
+		TotalCount: res.TotalCount,
+		Page:       res.Page,
+		PageSize:   res.PageSize,
+		Sort:       res.Sort,
+	})
+	return nil
+}
+
+func (h *crudHandler[T, ID]) get(c *Context) error {
+	entity, err := h.find(c)
+	if err != nil {
+		return err
+	}
+	c.JSON(http.StatusOK, h.response(entity))
+	return nil
+}
+
+func (h *crudHandler[T, ID]) create(c *Context) error {
+	if err := h.checkWritable(c, h.opts.request == nil); err != nil {
+		return err
+	}
+	entity := new(T)
+	if err := h.request(c, entity); err != nil {
+		return err
+	}
+	if err := h.runValidator(c, entity); err != nil {
+		return err
+	}
+	if err := h.repo.Insert(c.Request.Context(), entity); err != nil {
+		return repositoryError(err)
+	}
+	c.JSON(http.StatusCreated, h.response(entity))
+	return nil
+}
+
+func (h *crudHandler[T, ID]) update(c *Context) error {
+	return h.write(c, h.request, h.opts.request == nil)
+}
+
+func (h *crudHandler[T, ID]) patch(c *Context) error {
+	return h.write(c, decodeEntity[T], true)
+}
+
+// write đọc bản ghi hiện có, áp dụng body rồi ghi lại. Khóa chính luôn lấy từ path
+func (h *crudHandler[T, ID]) write(c *Context, apply func(c *Context, entity *T) error, decodesEntity bool) error {
+	if err := h.checkWritable(c, decodesEntity); err != nil {
+		return err
+	}
+	entity, err := h.find(c)
+	if err != nil {
+		return err
+	}
+	id, _ := h.parseID(c.Param("id"))
+	if err := apply(c, entity); err != nil {
+		return err
+	}
+	if pk := h.sch.PrioritizedPrimaryField; pk != nil {
+		if err := pk.Set(c.Request.Context(), reflect.ValueOf(entity).Elem(), id); err != nil {
+			return err
+		}
+	}
+	if err := h.runValidator(c, entity); err != nil {
+		return err
+	}
+	if err := h.repo.Update(c.Request.Context(), entity); err != nil {
+		return repositoryError(err)
+	}
+	c.JSON(http.StatusOK, h.response(entity))
+	return nil
+}
+
+func (h *crudHandler[T, ID]) delete(c *Context) error {
+	if _, err := h.find(c); err != nil {
+		return err
+	}
+	id, _ := h.parseID(c.Param("id"))
+	if err := h.repo.DeleteByID(c.Request.Context(), id); err != nil {
+		return repositoryError(err)
+	}
+	return nil
+}
+
+func (h *crudHandler[T, ID]) find(c *Context) (*T, error) {
+	id, err := h.parseID(c.Param("id"))
+	if err != nil {
+		return nil, NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid id %q", c.Param("id"))).WithCause(err)
+	}
+	entity, err := h.repo.FindByID(c.Request.Context(), id)
+	if err != nil {
+		return nil, repositoryError(err)
+	}
+	return entity, nil
+}
+
+func (h *crudHandler[T, ID]) runValidator(c *Context, entity *T) error {
+	if h.validate == nil {
+		return nil
+	}
+	err := h.validate(c.Request.Context(), entity)
+	var httpErr *HTTPError
+	if err == nil || errors.As(err, &httpErr) {
+		return err
+	}
+	return NewHTTPError(http.StatusBadRequest, err.Error()).WithCause(err)
+}
+
+// checkWritable kiểm tra body JSON là object và chỉ chứa các key được phép ghi.
+// decodesEntity cho biết body được đọc thẳng vào entity (luôn là JSON), ngược lại DTO có thể nhận body form
+func (h *crudHandler[T, ID]) checkWritable(c *Context, decodesEntity bool) error {
+	if mt := c.mediaType(); !decodesEntity && (mt == binding.MIMEPOSTForm || mt == binding.MIMEMultipartPOSTForm) {
+		return nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(c.Body(), &fields); err != nil || fields == nil {
+		return NewHTTPError(http.StatusBadRequest, "request body must be a JSON object").WithCause(err)
+	}
+	if h.opts.writable == nil && !decodesEntity {
+		return nil
+	}
+	var denied []string
+	for key := range fields {
+		if !h.writable(key) {
+			denied = append(denied, key)
+		}
+	}
+	if len(denied) > 0 {
+		sort.Strings(denied)
+		return NewHTTPError(http.StatusBadRequest, "fields are not writable: "+strings.Join(denied, ", ")).WithDetails(denied)
+	}
+	return nil
+}
+
+// writable cho biết client được gửi key, không có WithWritable thì chặn khóa chính và cột do gormx quản lý.
+// encoding/json khớp key với field không phân biệt hoa thường nên key chỉ đọc cũng được so như vậy
+func (h *crudHandler[T, ID]) writable(key string) bool {
+	if h.opts.writable != nil {
+		return h.opts.writable[key]
+	}
+	return !h.readOnly[strings.ToLower(key)]
+}
+
+// listQuery dựng Spec và phân trang từ query string
+func (h *crudHandler[T, ID]) listQuery(c *Context) (*gormx.Spec, int, int, error) {
+	spec := gormx.NewSpec()
+	page, pageSize := 1, h.opts.defaultPageSize
+
+	query := c.Request.URL.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	// thứ tự ổn định để cùng một query luôn sinh cùng câu SQL
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		raw := query.Get(key)
+		switch key {
+		case queryPage:
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 1 {
+				return nil, 0, 0, NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid page %q: must be a positive integer", raw))
+			}
+			page = n
+		case queryPageSize:
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 1 {
+				return nil, 0, 0, NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid page_size %q: must be a positive integer", raw))
+			}
+			pageSize = n
+		case querySort:
+			orders, err := h.sortOrders(raw)
+			if err != nil {
+				return nil, 0, 0, err
+			}
+			spec.OrderBy(orders...)
+		default:
+			cond, err := h.filter(key, raw)
+			if err != nil {
+				return nil, 0, 0, err
+			}
+			spec.Where(cond)
+		}
+	}
+
+	if h.opts.maxPageSize > 0 && pageSize > h.opts.maxPageSize {
+		pageSize = h.opts.maxPageSize
+	}
+	if len(spec.Orders()) == 0 {
+		if len(h.opts.defaultSort) > 0 {
+			spec.OrderBy(h.opts.defaultSort...)
+		} else if pk := h.sch.PrioritizedPrimaryField; pk != nil {
+			// sắp xếp ổn định để các trang không bị trùng/thiếu bản ghi
+			spec.OrderBy(gormx.Asc(pk.Name))
+		}
+	}
+	return spec, page, pageSize, nil
+}
+
+func (h *crudHandler[T, ID]) sortOrders(raw string) ([]gormx.Order, error) {
+	var orders []gormx.Order
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		order := gormx.Asc(strings.TrimPrefix(part, "-"))
+		order.Desc = strings.HasPrefix(part, "-")
+		if !h.opts.sortable[order.Column] {
+			return nil, NewHTTPError(http.StatusBadRequest, fmt.Sprintf("sorting by %q is not allowed", order.Column))
+		}
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
+
+// filter đọc key dạng "column" hoặc "column[op]"
+func (h *crudHandler[T, ID]) filter(key, raw string) (gormx.Condition, error) {
+	column, op := key, "eq"
+	if i := strings.IndexByte(key, '['); i > 0 && strings.HasSuffix(key, "]") {
+		column, op = key[:i], key[i+1:len(key)-1]
+	}
+	if !h.opts.filterable[column] {
+		return nil, NewHTTPError(http.StatusBadRequest, fmt.Sprintf("filtering by %q is not allowed", column))
+	}
+
+	switch op {
+	case "like":
+		return gormx.Like(column, "%"+raw+"%"), nil
+	case "null":
+		isNull, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid value %q for %s", raw, key))
+		}
+		if isNull {
+			return gormx.IsNull(column), nil
+		}
+		return gormx.Not(gormx.IsNull(column)), nil
+	case "in":
+		parts := strings.Split(raw, ",")
+		values := make([]any, len(parts))
+		for i, part := range parts {
+			v, err := h.filterValue(column, strings.TrimSpace(part))
+			if err != nil {
+				return nil, NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid value %q for %s", part, key)).WithCause(err)
+			}
+			values[i] = v
+		}
+		return gormx.In(column, values...), nil
+	}
+
+	build, ok := map[string]func(string, any) gormx.Condition{
+		"eq":  gormx.Eq,
+		"ne":  gormx.Ne,
+		"gt":  gormx.Gt,
+		"gte": gormx.Gte,
+		"lt":  gormx.Lt,
+		"lte": gormx.Lte,
+	}[op]
+	if !ok {
+		return nil, NewHTTPError(http.StatusBadRequest, fmt.Sprintf("unsupported filter operator %q", op))
+	}
+	v, err := h.filterValue(column, raw)
+	if err != nil {
+		return nil, NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid value %q for %s", raw, key)).WithCause(err)
+	}
+	return build(column, v), nil
+}
+
+// filterValue chuyển giá trị query về kiểu của field, cột không tra được giữ nguyên chuỗi
+func (h *crudHandler[T, ID]) filterValue(column, raw string) (any, error) {
+	f := h.sch.LookUpField(column)
+	if f == nil {
+		return raw, nil
+	}
+	t := f.FieldType
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	v := reflect.New(t)
+	if err := parseText(v, raw); errors.Is(err, errUnsupportedType) {
+		return raw, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return v.Elem().Interface(), nil
+}
+
+// managedColumns là các cột do gormx gán (mixin, tenant), client không được ghi trực tiếp
+var managedColumns = map[string]bool{
+	"version":    true,
+	"created_at": true,
+	"updated_at": true,
+	"created_by": true,
+	"updated_by": true,
+	"deleted_at": true,
+	"tenant_id":  true,
+}
+
+// readOnlyKeys trả về key JSON (chữ thường) của khóa chính và các cột do gormx quản lý
+func readOnlyKeys(sch *schema.Schema) map[string]bool {
+	keys := make(map[string]bool)
+	for _, f := range sch.Fields {
+		if !f.PrimaryKey && !managedColumns[f.DBName] {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = f.Name
+		}
+		keys[strings.ToLower(name)] = true
+	}
+	return keys
+}
+
+// decodeEntity là request mặc định: đọc body JSON vào entity rồi validate theo tag binding
+func decodeEntity[T any](c *Context, entity *T) error {
+	return c.BindJSON(entity)
+}
+
+// repositoryError chuyển lỗi của gormx sang HTTPError tương ứng
+func repositoryError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, gormx.ErrTenantMismatch):
+		return NewHTTPError(http.StatusNotFound, "resource not found").WithCause(err)
+	case errors.Is(err, gormx.ErrOptimisticLock):
+		return NewHTTPError(http.StatusConflict, "resource was modified by another request").WithCause(err)
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return NewHTTPError(http.StatusConflict, "resource already exists").WithCause(err)
+	case errors.Is(err, gormx.ErrUnknownColumn), errors.Is(err, gormx.ErrMissingTenant), errors.Is(err, gormx.ErrInvalidTenant):
+		return NewHTTPError(http.StatusBadRequest, err.Error()).WithCause(err)
+	}
+	return err
+}
+
+var errUnsupportedType = errors.New("unsupported type")
+
+func parseIDText[ID any](raw string) (ID, error) {
+	var id ID
+	err := parseText(reflect.ValueOf(&id), raw)
+	return id, err
+}
+
+// parseText gán raw vào *v theo kiểu: TextUnmarshaler, chuỗi, số hoặc bool
+func parseText(ptr reflect.Value, raw string) error {
+	if u, ok := ptr.Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(raw))
+	}
+	v := ptr.Elem()
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	default:
+		return fmt.Errorf("%w %s", errUnsupportedType, v.Type())
+	}
+	return nil
+}
+
+func addSet(set map[string]bool, keys []string) map[string]bool {
+	if set == nil {
+		set = make(map[string]bool, len(keys))
+	}
+	for _, k := range keys {
+		set[k] = true
+	}
+	return set
+}
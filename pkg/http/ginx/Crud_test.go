@@ -0,0 +1,288 @@
+package ginx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.io/xhkzeroone/goframex/pkg/database/gormx"
+	"github.io/xhkzeroone/goframex/pkg/database/gormx/gormxtest"
+)
+
+type crudItem struct {
+	ID       uint    `gorm:"primaryKey" json:"id"`
+	Name     string  `json:"name" binding:"required"`
+	Category string  `json:"category"`
+	Price    int     `json:"price"`
+	Note     *string `json:"note"`
+	gormx.Versioned
+	gormx.Auditable
+}
+
+type crudItemForm struct {
+	Name  string `form:"name" json:"name" binding:"required"`
+	Price int    `form:"price" json:"price"`
+}
+
+type crudTest struct {
+	t    *testing.T
+	srv  *Server
+	repo *gormx.Repository[crudItem, uint]
+}
+
+func newCRUDTest(t *testing.T, opts ...CRUDOption) *crudTest {
+	t.Helper()
+	ds := gormxtest.New(t, gormxtest.WithModels(&crudItem{}))
+	repo := gormx.NewRepository[crudItem, uint](ds)
+	for _, item := range []crudItem{
+		{Name: "apple", Category: "fruit", Price: 30},
+		{Name: "banana", Category: "fruit", Price: 10},
+		{Name: "carrot", Category: "vegetable", Price: 20},
+		{Name: "durian", Category: "fruit", Price: 90},
+		{Name: "eggplant", Category: "vegetable", Price: 20},
+	} {
+		if err := repo.Insert(context.Background(), &item); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+	srv := New(&Config{Mode: "test"})
+	CRUD[crudItem, uint](srv.Group("/items"), repo, opts...)
+	return &crudTest{t: t, srv: srv, repo: repo}
+}
+
+func (ct *crudTest) do(method, target, contentType, body string) *httptest.ResponseRecorder {
+	ct.t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	rec := httptest.NewRecorder()
+	ct.srv.ServeHTTP(rec, req)
+	return rec
+}
+
+func (ct *crudTest) send(method, target, body string) *httptest.ResponseRecorder {
+	ct.t.Helper()
+	return ct.do(method, target, "application/json", body)
+}
+
+// list trả về tên các item của trang và tổng số bản ghi
+func (ct *crudTest) list(query string) ([]string, int64) {
+	ct.t.Helper()
+	rec := ct.send(http.MethodGet, "/items"+query, "")
+	if rec.Code != http.StatusOK {
+		ct.t.Fatalf("GET %s: code %d, body %s", query, rec.Code, rec.Body)
+	}
+	var page gormx.Page[crudItem]
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+		ct.t.Fatalf("decode page: %v", err)
+	}
+	names := make([]string, len(page.Items))
+	for i, item := range page.Items {
+		names[i] = item.Name
+	}
+	return names, page.TotalCount
+}
+
+func (ct *crudTest) item(id uint) crudItem {
+	ct.t.Helper()
+	item, err := ct.repo.FindByID(context.Background(), id)
+	if err != nil {
+		ct.t.Fatalf("find %d: %v", id, err)
+	}
+	return *item
+}
+
+func TestCRUDListFiltersSortAndPages(t *testing.T) {
+	ct := newCRUDTest(t, WithFilterable("category", "price", "name", "note"), WithSortable("price", "name"), WithPageSize(2, 3))
+
+	for _, tc := range []struct {
+		query string
+		want  []string
+		total int64
+	}{
+		{"", []string{"apple", "banana"}, 5},
+		{"?page=3", []string{"eggplant"}, 5},
+		{"?page_size=50", []string{"apple", "banana", "carrot"}, 5},
+		{"?category=fruit&page_size=3", []string{"apple", "banana", "durian"}, 3},
+		{"?price[gte]=20&price[lt]=90&sort=-price,name&page_size=3", []string{"apple", "carrot", "eggplant"}, 3},
+		{"?category[ne]=fruit&sort=-name", []string{"eggplant", "carrot"}, 2},
+		{"?name[like]=an&sort=name", []string{"banana", "durian"}, 3},
+		{"?name[in]=durian,apple,kiwi&sort=-name", []string{"durian", "apple"}, 2},
+		{"?note[null]=true&sort=price", []string{"banana", "carrot"}, 5},
+		{"?note[null]=false", []string{}, 0},
+	} {
+		names, total := ct.list(tc.query)
+		if !reflect.DeepEqual(names, tc.want) || total != tc.total {
+			t.Errorf("GET %q = %v (total %d), want %v (total %d)", tc.query, names, total, tc.want, tc.total)
+		}
+	}
+
+	for _, query := range []string{
+		"?unknown=1",
+		"?Price=10",
+		"?price[regex]=1",
+		"?price=abc",
+		"?price[in]=1,x",
+		"?note[null]=maybe",
+		"?sort=category",
+		"?page=0",
+		"?page_size=x",
+	} {
+		if rec := ct.send(http.MethodGet, "/items"+query, ""); rec.Code != http.StatusBadRequest {
+			t.Errorf("GET %q: code %d, want 400", query, rec.Code)
+		}
+	}
+}
+
+func TestCRUDGetCreateDelete(t *testing.T) {
+	ct := newCRUDTest(t)
+
+	rec := ct.send(http.MethodGet, "/items/2", "")
+	var got crudItem
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); rec.Code != http.StatusOK || err != nil || got.Name != "banana" {
+		t.Fatalf("GET /items/2: code %d, body %s", rec.Code, rec.Body)
+	}
+	for _, target := range []string{"/items/99", "/items/abc"} {
+		want := http.StatusNotFound
+		if target == "/items/abc" {
+			want = http.StatusBadRequest
+		}
+		if rec := ct.send(http.MethodGet, target, ""); rec.Code != want {
+			t.Errorf("GET %s: code %d, want %d", target, rec.Code, want)
+		}
+	}
+
+	rec = ct.send(http.MethodPost, "/items", `{"name":"fig","price":15}`)
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); rec.Code != http.StatusCreated || err != nil || got.ID == 0 || got.Version != 1 {
+		t.Fatalf("POST: code %d, body %s", rec.Code, rec.Body)
+	}
+	if rec := ct.send(http.MethodPost, "/items", `{"price":15}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("POST without required name: code %d", rec.Code)
+	}
+	if rec := ct.send(http.MethodPost, "/items", `[1]`); rec.Code != http.StatusBadRequest {
+		t.Errorf("POST non-object body: code %d", rec.Code)
+	}
+
+	if rec := ct.send(http.MethodDelete, "/items/1", ""); rec.Code >= 300 {
+		t.Fatalf("DELETE: code %d, body %s", rec.Code, rec.Body)
+	}
+	if rec := ct.send(http.MethodDelete, "/items/1", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("second DELETE: code %d, want 404", rec.Code)
+	}
+}
+
+func TestCRUDPatchMergesAndProtectsManagedColumns(t *testing.T) {
+	ct := newCRUDTest(t)
+	before := ct.item(1)
+
+	rec := ct.send(http.MethodPatch, "/items/1", `{"price":35,"note":"ripe"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("PATCH: code %d, body %s", rec.Code, rec.Body)
+	}
+	after := ct.item(1)
+	if after.Name != "apple" || after.Category != "fruit" || after.Price != 35 || after.Note == nil || *after.Note != "ripe" {
+		t.Errorf("after PATCH = %+v, want only price and note changed", after)
+	}
+	if after.Version != before.Version+1 || !after.CreatedAt.Equal(before.CreatedAt) {
+		t.Errorf("version %d, created_at %v; want version bumped and created_at kept", after.Version, after.CreatedAt)
+	}
+
+	// Khóa chính, version và cột audit không được ghi qua body, kể cả khi viết khác hoa thường
+	for _, body := range []string{
+		`{"id":2}`,
+		`{"Version":1}`,
+		`{"price":1,"createdAt":"2020-01-01T00:00:00Z"}`,
+		`{"CreatedBy":"mallory"}`,
+		`{"updatedat":"2020-01-01T00:00:00Z"}`,
+	} {
+		rec := ct.send(http.MethodPatch, "/items/1", body)
+		if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "not writable") {
+			t.Errorf("PATCH %s: code %d, body %s", body, rec.Code, rec.Body)
+		}
+	}
+	if rec := ct.send(http.MethodPost, "/items", `{"id":42,"name":"kiwi"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("POST with id: code %d, want 400", rec.Code)
+	}
+	if got := ct.item(1); got.Price != 35 || got.Version != after.Version {
+		t.Errorf("rejected PATCH changed the row: %+v", got)
+	}
+	if rec := ct.send(http.MethodPatch, "/items/99", `{"price":1}`); rec.Code != http.StatusNotFound {
+		t.Errorf("PATCH missing item: code %d, want 404", rec.Code)
+	}
+}
+
+func TestCRUDVersionConflict(t *testing.T) {
+	var repo *gormx.Repository[crudItem, uint]
+	ct := newCRUDTest(t, WithValidator(func(ctx context.Context, item *crudItem) error {
+		if item.Price < 0 {
+			return errors.New("price must not be negative")
+		}
+		// Request khác ghi vào bản ghi trong lúc request này đang xử lý
+		_, err := repo.UpdateWhere(ctx, gormx.NewSpec(gormx.Eq("id", item.ID)), map[string]any{"category": "sold"})
+		return err
+	}))
+	repo = ct.repo
+
+	if rec := ct.send(http.MethodPatch, "/items/1", `{"price":-1}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid entity: code %d, want 400", rec.Code)
+	}
+	if rec := ct.send(http.MethodPatch, "/items/1", `{"price":1}`); rec.Code != http.StatusConflict {
+		t.Fatalf("concurrent PATCH: code %d, body %s", rec.Code, rec.Body)
+	}
+	if got := ct.item(1); got.Price != 30 || got.Category != "sold" {
+		t.Errorf("after conflict = %+v, want the concurrent write only", got)
+	}
+}
+
+func TestCRUDWritableAndRequestDTO(t *testing.T) {
+	ct := newCRUDTest(t,
+		WithWritable("name", "price"),
+		WithRequestDTO(func(dto *crudItemForm, item *crudItem) error {
+			item.Name, item.Price = dto.Name, dto.Price
+			return nil
+		}),
+		WithOperations(OpCreate, OpUpdate, OpPatch),
+	)
+
+	rec := ct.send(http.MethodPatch, "/items/2", `{"price":12,"category":"x","id":9}`)
+	var body ErrorBody
+	_ = json.Unmarshal(rec.Body.Bytes(), &body)
+	if rec.Code != http.StatusBadRequest || !reflect.DeepEqual(body.Details, []any{"category", "id"}) {
+		t.Errorf("PATCH with denied keys: code %d, details %v", rec.Code, body.Details)
+	}
+	if rec := ct.send(http.MethodPatch, "/items/2", `{"price":12}`); rec.Code != http.StatusOK || ct.item(2).Price != 12 {
+		t.Errorf("PATCH writable key: code %d, body %s", rec.Code, rec.Body)
+	}
+
+	// DTO quyết định field được ghi, PUT giữ nguyên các field không map
+	if rec := ct.send(http.MethodPut, "/items/2", `{"name":"plantain","price":11}`); rec.Code != http.StatusOK {
+		t.Fatalf("PUT: code %d, body %s", rec.Code, rec.Body)
+	}
+	if got := ct.item(2); got.Name != "plantain" || got.Price != 11 || got.Category != "fruit" {
+		t.Errorf("after PUT = %+v", got)
+	}
+	if rec := ct.send(http.MethodPut, "/items/2", `{"name":"x","note":"y"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("PUT with denied key: code %d, want 400", rec.Code)
+	}
+
+	// Body form của DTO không bị kiểm tra như JSON
+	form := url.Values{"name": {"grape"}, "price": {"25"}}.Encode()
+	rec = ct.do(http.MethodPost, "/items", "application/x-www-form-urlencoded", form)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("POST form: code %d, body %s", rec.Code, rec.Body)
+	}
+	if items, err := ct.repo.FindWhere(context.Background(), "name = ?", "grape"); err != nil || len(items) != 1 || items[0].Price != 25 {
+		t.Errorf("form item = %+v, %v", items, err)
+	}
+
+	if rec := ct.send(http.MethodGet, "/items", ""); rec.Code != http.StatusNotFound && rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("disabled list route: code %d", rec.Code)
+	}
+}
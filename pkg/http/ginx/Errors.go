@@ -0,0 +1,82 @@
+package ginx
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrorBody là định dạng JSON chung cho mọi response lỗi
+type ErrorBody struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+	Code    int    `json:"code"`
+	Details any    `json:"details,omitempty"`
+}
+
+// HTTPError là lỗi kèm HTTP status. Handler trả về HTTPError (có thể được wrap)
+// sẽ được render với status đó, các lỗi khác trả về 500
+type HTTPError struct {
+	Status  int
+	Message string
+	Details any
+	Err     error
+}
+
+// NewHTTPError tạo lỗi với status và message trả về cho client
+func NewHTTPError(status int, message string) *HTTPError {
+	return &HTTPError{Status: status, Message: message}
+}
+
+// WithDetails gắn thêm chi tiết (ví dụ danh sách field lỗi) vào response
+func (e *HTTPError) WithDetails(details any) *HTTPError {
+	e.Details = details
+	return e
+}
+
+// WithCause giữ lỗi gốc để errors.Is/As vẫn dùng được, không hiển thị cho client
+func (e *HTTPError) WithCause(err error) *HTTPError {
+	e.Err = err
+	return e
+}
+
+func (e *HTTPError) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// errorResponse chuyển lỗi của handler thành status và body
+func errorResponse(err error) (int, ErrorBody) {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return http.StatusBadRequest, ErrorBody{
+			Error:   http.StatusText(http.StatusBadRequest),
+			Message: "validation failed",
+			Code:    http.StatusBadRequest,
+			Details: validationErr.Fields,
+		}
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		status := httpErr.Status
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		return status, ErrorBody{
+			Error:   http.StatusText(status),
+			Message: httpErr.Message,
+			Code:    status,
+			Details: httpErr.Details,
+		}
+	}
+	return http.StatusInternalServerError, ErrorBody{
+		Error:   http.StatusText(http.StatusInternalServerError),
+		Message: err.Error(),
+		Code:    http.StatusInternalServerError,
+	}
+}
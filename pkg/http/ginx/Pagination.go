@@ -0,0 +1,30 @@
+package ginx
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// CursorQuery là tham số phân trang cursor lấy từ query string
+type CursorQuery struct {
+	Cursor string `json:"cursor"`
+	Limit  int    `json:"limit"`
+}
+
+// ParseCursorQuery đọc "cursor" và "limit" từ query string.
+// limit trống sẽ dùng defaultLimit, lớn hơn maxLimit sẽ bị giới hạn về maxLimit
+func (c *Context) ParseCursorQuery(defaultLimit, maxLimit int) (*CursorQuery, error) {
+	q := &CursorQuery{Cursor: c.query["cursor"], Limit: defaultLimit}
+
+	if raw := c.query["limit"]; raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			return nil, fmt.Errorf("invalid limit %q: must be a positive integer", raw)
+		}
+		q.Limit = limit
+	}
+	if maxLimit > 0 && q.Limit > maxLimit {
+		q.Limit = maxLimit
+	}
+	return q, nil
+}
@@ -2,7 +2,6 @@ package ginx
 
 import (
 	"context"
-	"encoding/json"
 	"io"
 	"log"
 	"net/http"
@@ -37,10 +36,6 @@ func (c *Context) JSON(status int, data any) {
 	c.response = data
 }
 
-func (c *Context) Bind(data any) error {
-	return json.Unmarshal(c.bodyBytes, data)
-}
-
 type HandlerFunc func(*Context) error
 type Middleware func(HandlerFunc) HandlerFunc
 
@@ -94,7 +89,7 @@ func (m *MiddlewareChain) Wrap(handler HandlerFunc) gin.HandlerFunc {
 
 		// Gọi handler
 		if err := final(myCtx); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			c.JSON(errorResponse(err))
 			return
 		}
 
@@ -163,6 +158,13 @@ func (g *RouterGroup) PUT(path string, handler HandlerFunc, middleware ...Middle
 	g.group.PUT(path, final.Wrap(handler))
 }
 
+func (g *RouterGroup) PATCH(path string, handler HandlerFunc, middleware ...Middleware) {
+	chain := &MiddlewareChain{}
+	chain.Use(middleware...)
+	final := Compose(g.collectMiddleware(), chain)
+	g.group.PATCH(path, final.Wrap(handler))
+}
+
 func (g *RouterGroup) DELETE(path string, handler HandlerFunc, middleware ...Middleware) {
 	chain := &MiddlewareChain{}
 	chain.Use(middleware...)
@@ -192,6 +194,8 @@ func registerRoutes(group *RouterGroup, routes []Route) {
 			group.group.POST(r.Path, final.Wrap(r.Handler))
 		case http.MethodPut:
 			group.group.PUT(r.Path, final.Wrap(r.Handler))
+		case http.MethodPatch:
+			group.group.PATCH(r.Path, final.Wrap(r.Handler))
 		case http.MethodDelete:
 			group.group.DELETE(r.Path, final.Wrap(r.Handler))
 		default:
@@ -269,6 +273,10 @@ func (s *Server) PUT(relativePath string, handler HandlerFunc, middleware ...Mid
 	s.rootGroup.PUT(relativePath, handler, middleware...)
 }
 
+func (s *Server) PATCH(relativePath string, handler HandlerFunc, middleware ...Middleware) {
+	s.rootGroup.PATCH(relativePath, handler, middleware...)
+}
+
 func (s *Server) DELETE(relativePath string, handler HandlerFunc, middleware ...Middleware) {
 	s.rootGroup.DELETE(relativePath, handler, middleware...)
 }
@@ -73,7 +73,7 @@ func (f *DynamicFormatter) Format(entry *logrus.Entry) ([]byte, error) {
 		}
 	}
 
-	return []byte(out + "\n"), nil
+	return []byte(RedactSecrets(out) + "\n"), nil
 }
 
 func extractPlaceholders(pattern string) []string {
@@ -46,6 +46,7 @@ func (f *JSONFormatter) Format(entry *logrus.Entry) ([]byte, error) {
 	} else {
 		masked = string(serialized)
 	}
+	masked = RedactSecrets(masked)
 
 	return []byte(masked + "\n"), nil
 }
@@ -0,0 +1,73 @@
+package logrusx
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const secretMask = "******"
+
+// Giá trị ngắn hơn mức này không được che để tránh che nhầm các chuỗi phổ biến
+const minSecretLength = 4
+
+var (
+	secretsMu sync.RWMutex
+	secrets   = make(map[string]struct{})
+	// secretForms là các dạng cần che (nguyên bản và đã escape JSON), dài trước ngắn sau
+	secretForms []string
+)
+
+// MarkSecret đánh dấu các giá trị bí mật (mật khẩu, token...) để formatter che đi khi ghi log,
+// kể cả khi giá trị nằm trong chuỗi JSON đã escape một hoặc hai lần (ví dụ field chứa \"password\":\"...\")
+func MarkSecret(values ...string) {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	for _, v := range values {
+		if len(v) < minSecretLength {
+			continue
+		}
+		if _, ok := secrets[v]; ok {
+			continue
+		}
+		secrets[v] = struct{}{}
+		for _, form := range escapedForms(v) {
+			if !slices.Contains(secretForms, form) {
+				secretForms = append(secretForms, form)
+			}
+		}
+	}
+	// Dạng dài được thay trước để dạng ngắn hơn không cắt dở nó
+	slices.SortStableFunc(secretForms, func(a, b string) int { return len(b) - len(a) })
+}
+
+// escapedForms trả về giá trị và các dạng của nó sau khi escape JSON một, hai lần (có và không escape HTML)
+func escapedForms(v string) []string {
+	forms := []string{v}
+	for _, html := range []bool{true, false} {
+		once := jsonEscape(v, html)
+		forms = append(forms, once, jsonEscape(once, html))
+	}
+	return forms
+}
+
+func jsonEscape(s string, html bool) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(html)
+	_ = enc.Encode(s)
+	out := strings.TrimSuffix(buf.String(), "\n")
+	return out[1 : len(out)-1]
+}
+
+// RedactSecrets thay thế mọi giá trị đã đánh dấu trong message bằng "******"
+func RedactSecrets(message string) string {
+	secretsMu.RLock()
+	defer secretsMu.RUnlock()
+	for _, s := range secretForms {
+		message = strings.ReplaceAll(message, s, secretMask)
+	}
+	return message
+}
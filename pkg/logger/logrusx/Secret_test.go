@@ -0,0 +1,47 @@
+package logrusx
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestRedactSecretsEscapedForms(t *testing.T) {
+	plain := "s3cr3t-token"
+	special := `p"ss\w<rd>&1`
+	MarkSecret(plain, special, "abc")
+
+	body, _ := json.Marshal(map[string]string{"password": special, "token": plain})
+	entry := &logrus.Entry{
+		Message: "login with " + special + " and " + plain,
+		Data: logrus.Fields{
+			"request": string(body),
+			"raw":     special,
+		},
+	}
+	out, err := (&JSONFormatter{}).Format(entry)
+	if err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	got := string(out)
+	for _, leaked := range []string{plain, `p\"ss`, `p\\\"ss`, `\u003crd`, `<rd>`} {
+		if strings.Contains(got, leaked) {
+			t.Errorf("log leaks %q: %s", leaked, got)
+		}
+	}
+	if !strings.Contains(got, secretMask) {
+		t.Errorf("log has no mask: %s", got)
+	}
+
+	for _, tc := range []struct{ in, want string }{
+		{"short abc stays", "short abc stays"},
+		{`{\"password\":\"` + jsonEscape(jsonEscape(special, false), false) + `\"}`, `{\"password\":\"******\"}`},
+		{"plain " + special, "plain ******"},
+	} {
+		if got := RedactSecrets(tc.in); got != tc.want {
+			t.Errorf("RedactSecrets(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
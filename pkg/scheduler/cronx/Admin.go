@@ -0,0 +1,153 @@
+package cronx
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.io/xhkzeroone/goframex/pkg/http/ginx"
+)
+
+// AdminRoutes trả về các route quản trị job, mount bằng ginx.Server.RoutesGroup, ví dụ
+// srv.RoutesGroup("/admin/cron", cronx.AdminRoutes(c, authMiddleware)...):
+//
+//	GET  /jobs                   danh sách job
+//	GET  /jobs/<name>            chi tiết job
+//	GET  /jobs/<name>/history    lịch sử chạy của job (?limit=)
+//	POST /jobs/<name>/trigger    chạy ngay
+//	POST /jobs/<name>/pause      tạm dừng chạy theo lịch
+//	POST /jobs/<name>/resume     chạy lại theo lịch
+//	GET  /history                lịch sử chạy của mọi job (?limit=)
+//
+// <name> được khớp bằng wildcard nên dùng được tên chứa "/" (tên function của AddJob,
+// ví dụ /jobs/github.com/acme/app/jobs.Cleanup/trigger).
+// Pause/Resume chỉ đổi trạng thái trong bộ nhớ của instance nhận request: khi chạy nhiều replica
+// cần gọi tới từng instance, hoặc tắt job trên mọi replica bằng JobEnabledKey và Cron.Reload.
+// Các route này cho phép chạy job tùy ý nên cần middleware xác thực
+func AdminRoutes(c *Cron, middleware ...ginx.Middleware) []ginx.Route {
+	a := &admin{cron: c}
+	route := func(method, path string, handler ginx.HandlerFunc) ginx.Route {
+		return ginx.Route{Path: path, Method: method, Handler: handler, Middleware: middleware}
+	}
+	return []ginx.Route{
+		route(http.MethodGet, "/jobs", a.list),
+		route(http.MethodGet, "/jobs/*path", a.get),
+		route(http.MethodPost, "/jobs/*path", a.action),
+		route(http.MethodGet, "/history", a.allHistory),
+	}
+}
+
+const (
+	actionHistory = "history"
+	actionTrigger = "trigger"
+	actionPause   = "pause"
+	actionResume  = "resume"
+)
+
+type admin struct {
+	cron *Cron
+}
+
+func (a *admin) list(ctx *ginx.Context) error {
+	ctx.JSON(http.StatusOK, a.cron.Jobs())
+	return nil
+}
+
+func (a *admin) get(ctx *ginx.Context) error {
+	name, action := a.jobPath(ctx, actionHistory)
+	if action == actionHistory {
+		return a.history(ctx, name)
+	}
+	return a.job(ctx, name)
+}
+
+func (a *admin) action(ctx *ginx.Context) error {
+	name, action := a.jobPath(ctx, actionTrigger, actionPause, actionResume)
+	switch action {
+	case actionTrigger:
+		if err := a.cron.Trigger(name); err != nil {
+			return adminError(err)
+		}
+		ctx.JSON(http.StatusAccepted, map[string]string{"status": "triggered"})
+		return nil
+	case actionPause:
+		if err := a.cron.Pause(name); err != nil {
+			return adminError(err)
+		}
+		return a.job(ctx, name)
+	case actionResume:
+		if err := a.cron.Resume(name); err != nil {
+			return adminError(err)
+		}
+		return a.job(ctx, name)
+	}
+	return ginx.NewHTTPError(http.StatusNotFound, fmt.Sprintf("unknown action for %q, want one of trigger, pause, resume", name))
+}
+
+// jobPath tách "/<name>/<action>" của wildcard. Chỉ tách khi phần trước action là job đã đăng ký,
+// còn lại cả path là tên job và action rỗng
+func (a *admin) jobPath(ctx *ginx.Context, actions ...string) (string, string) {
+	path := strings.TrimPrefix(ctx.Param("path"), "/")
+	for _, action := range actions {
+		name, ok := strings.CutSuffix(path, "/"+action)
+		if !ok {
+			continue
+		}
+		if _, err := a.cron.Job(name); err == nil {
+			return name, action
+		}
+	}
+	return path, ""
+}
+
+func (a *admin) job(ctx *ginx.Context, name string) error {
+	info, err := a.cron.Job(name)
+	if err != nil {
+		return adminError(err)
+	}
+	ctx.JSON(http.StatusOK, info)
+	return nil
+}
+
+func (a *admin) allHistory(ctx *ginx.Context) error {
+	return a.history(ctx, "")
+}
+
+func (a *admin) history(ctx *ginx.Context, name string) error {
+	if name != "" {
+		if _, err := a.cron.Job(name); err != nil {
+			return adminError(err)
+		}
+	}
+	limit := 50
+	if raw := ctx.Query()["limit"]; raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > 1000 {
+			return ginx.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid limit %q: must be between 1 and 1000", raw))
+		}
+		limit = n
+	}
+	items, err := a.cron.History(ctx.Request.Context(), name, limit)
+	if err != nil {
+		return err
+	}
+	if items == nil {
+		items = []Execution{}
+	}
+	ctx.JSON(http.StatusOK, items)
+	return nil
+}
+
+func adminError(err error) error {
+	switch {
+	case errors.Is(err, ErrJobNotFound):
+		return ginx.NewHTTPError(http.StatusNotFound, err.Error()).WithCause(err)
+	case errors.Is(err, ErrJobDisabled):
+		return ginx.NewHTTPError(http.StatusConflict, err.Error()).WithCause(err)
+	case errors.Is(err, ErrShutdown):
+		return ginx.NewHTTPError(http.StatusServiceUnavailable, err.Error()).WithCause(err)
+	}
+	return err
+}
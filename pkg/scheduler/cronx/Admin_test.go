@@ -0,0 +1,78 @@
+package cronx
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.io/xhkzeroone/goframex/pkg/http/ginx"
+)
+
+var adminRuns = make(chan struct{}, 1)
+
+func adminTestJob() {
+	adminRuns <- struct{}{}
+}
+
+func TestAdminRoutesReachFunctionNames(t *testing.T) {
+	c := New()
+	defer c.Shutdown(context.Background())
+	if err := c.AddJob("@every 1h", adminTestJob); err != nil {
+		t.Fatalf("add job: %v", err)
+	}
+	name := c.Jobs()[0].Name
+	if !strings.Contains(name, "/") {
+		t.Fatalf("job name %q has no slash, test does not cover wildcard routes", name)
+	}
+	c.Start()
+
+	srv := ginx.New(&ginx.Config{Mode: "test"})
+	srv.RoutesGroup("/admin/cron", AdminRoutes(c)...)
+	do := func(method, path string) (int, JobInfo) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(method, "/admin/cron"+path, nil))
+		var info JobInfo
+		if strings.HasPrefix(rec.Body.String(), "{") {
+			_ = json.Unmarshal(rec.Body.Bytes(), &info)
+		}
+		return rec.Code, info
+	}
+
+	if code, info := do(http.MethodGet, "/jobs/"+name); code != http.StatusOK || info.Name != name {
+		t.Fatalf("get job: code %d, name %q", code, info.Name)
+	}
+	if code, info := do(http.MethodPost, "/jobs/"+name+"/pause"); code != http.StatusOK || !info.Paused {
+		t.Errorf("pause: code %d, paused %v", code, info.Paused)
+	}
+	if code, info := do(http.MethodPost, "/jobs/"+name+"/resume"); code != http.StatusOK || info.Paused {
+		t.Errorf("resume: code %d, paused %v", code, info.Paused)
+	}
+	if code, _ := do(http.MethodPost, "/jobs/"+name+"/trigger"); code != http.StatusAccepted {
+		t.Errorf("trigger: code %d", code)
+	}
+	select {
+	case <-adminRuns:
+	case <-time.After(2 * time.Second):
+		t.Error("triggered job did not run")
+	}
+	if code, _ := do(http.MethodGet, "/jobs/"+name+"/history"); code != http.StatusOK {
+		t.Errorf("history: code %d", code)
+	}
+
+	for _, tc := range []struct{ method, path string }{
+		{http.MethodGet, "/jobs/missing"},
+		{http.MethodGet, "/jobs/missing/history"},
+		{http.MethodPost, "/jobs/" + name},
+		{http.MethodPost, "/jobs/" + name + "/explode"},
+		{http.MethodPost, "/jobs/missing/trigger"},
+	} {
+		if code, _ := do(tc.method, tc.path); code != http.StatusNotFound {
+			t.Errorf("%s %s: code %d, want 404", tc.method, tc.path, code)
+		}
+	}
+}
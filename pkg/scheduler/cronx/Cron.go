@@ -1,64 +1,243 @@
 package cronx
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/robfig/cron/v3"
-	"github.com/spf13/viper"
 	"log"
+	"os"
 	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
 )
 
-var cronParser = cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+// cronParser nhận biểu thức 6 trường (có giây), tiền tố CRON_TZ=<múi giờ> và descriptor như @daily, @every 5m
+var cronParser = cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// Lookup tra cứu biểu thức cron theo tên key trong config (ví dụ ymlx.Config)
+type Lookup interface {
+	Lookup(key string) (string, bool)
+}
+
+// LookupFunc cho phép dùng function làm Lookup
+type LookupFunc func(key string) (string, bool)
+
+func (f LookupFunc) Lookup(key string) (string, bool) {
+	return f(key)
+}
+
+type Option func(c *Cron)
+
+// WithLookup cho phép job khai báo CronExpr là tên key trong config thay vì biểu thức
+func WithLookup(lookup Lookup) Option {
+	return func(c *Cron) {
+		c.lookup = lookup
+	}
+}
+
+// WithTimeout thời gian chạy tối đa mặc định của mỗi job, 0 là không giới hạn
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Cron) {
+		c.timeout = timeout
+	}
+}
+
+// WithLocation múi giờ mặc định của các biểu thức cron (mặc định time.Local)
+func WithLocation(loc *time.Location) Option {
+	return func(c *Cron) {
+		c.location = loc
+	}
+}
+
+// WithJitter trì hoãn ngẫu nhiên trong [0, max) trước mỗi lần chạy theo lịch để các replica không chạy cùng một giây.
+// Khi dùng WithLocker, khóa được lấy trước khi trì hoãn nên mỗi tick vẫn chỉ chạy trên một replica
+func WithJitter(max time.Duration) Option {
+	return func(c *Cron) {
+		c.jitter = max
+	}
+}
+
+// WithOverlap OverlapPolicy mặc định của các job (mặc định OverlapAllow)
+func WithOverlap(policy OverlapPolicy) Option {
+	return func(c *Cron) {
+		c.overlap = policy
+	}
+}
 
 type Cron struct {
 	*cron.Cron
+	lookup   Lookup
+	locker   Locker
+	lockOpts *lockOptions
+	timeout  time.Duration
+	overlap  OverlapPolicy
+	location *time.Location
+	jitter   time.Duration
+	history  HistoryStore
+	instance string
+
+	reloadInterval time.Duration
+	reloadOnce     sync.Once
+
+	// ctx bị hủy khi Shutdown, là context cha của mọi lần chạy job
+	ctx     context.Context
+	cancel  context.CancelFunc
+	mu      sync.Mutex
+	closed  bool
+	wg      sync.WaitGroup
+	entries map[string]*entry
+	names   []string
 }
 
-func New() *Cron {
-	return &Cron{
-		Cron: cron.New(cron.WithSeconds()),
+func New(opts ...Option) *Cron {
+	ctx, cancel := context.WithCancel(context.Background())
+	instance, _ := os.Hostname()
+	c := &Cron{
+		ctx:      ctx,
+		cancel:   cancel,
+		location: time.Local,
+		instance: instance,
+		entries:  make(map[string]*entry),
 	}
+	for _, o := range opts {
+		o(c)
+	}
+	c.Cron = cron.New(cron.WithSeconds(), cron.WithLocation(c.location))
+	return c
 }
 
-func resolveCronExpr(expr string) (string, error) {
+func (c *Cron) resolveCronExpr(expr string) (string, error) {
 	if isValidCronExpr(expr) {
 		return expr, nil
 	}
-	expr2 := viper.GetString(expr)
-	if isValidCronExpr(expr2) {
-		return expr2, nil
+	if c.lookup != nil {
+		if expr2, ok := c.lookup.Lookup(expr); ok && isValidCronExpr(expr2) {
+			return expr2, nil
+		}
 	}
 	return "", fmt.Errorf("invalid cronx expression '%s' (and not found in config)", expr)
 }
 
+// AddJob đăng ký function, khi dùng WithLocker tên khóa là tên function
 func (c *Cron) AddJob(cronExpr string, jobFunc func()) error {
-	expr, err := resolveCronExpr(cronExpr)
+	name := runtime.FuncForPC(reflect.ValueOf(jobFunc).Pointer()).Name()
+	return c.addEntry(cronExpr, c.newEntry(name, nil, func(context.Context) error {
+		jobFunc()
+		return nil
+	}))
+}
+
+func (c *Cron) addEntry(cronExpr string, e *entry) error {
+	expr, err := c.resolveCronExpr(cronExpr)
+	if err != nil {
+		return err
+	}
+	enabled, err := c.resolveEnabled(e.enabledKey)
 	if err != nil {
 		return err
 	}
-	if _, err := c.AddFunc(expr, jobFunc); err != nil {
+	sched, err := c.parseSchedule(expr, e.location)
+	if err != nil {
 		return fmt.Errorf("failed to add cronx job: %v", err)
 	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[e.name]; ok {
+		return errors.New("job is already registered")
+	}
+	e.source = cronExpr
+	c.schedule(e, sched, expr, enabled)
+	c.entries[e.name] = e
+	c.names = append(c.names, e.name)
 	return nil
 }
 
-func (c *Cron) AddJobs(jobs ...Job) {
-	if len(jobs) == 0 {
-		return
+// schedule thay lịch của e, job bị tắt thì không có lịch. Gọi khi giữ c.mu
+func (c *Cron) schedule(e *entry, sched cron.Schedule, expr string, enabled bool) {
+	if e.id != 0 {
+		c.Cron.Remove(e.id)
+		e.id = 0
+	}
+	if enabled {
+		e.id = c.Schedule(sched, cron.FuncJob(func() { c.tick(e, TriggerSchedule) }))
+	}
+	e.spec, e.enabled = expr, enabled
+}
+
+// parseSchedule áp dụng loc (hoặc múi giờ mặc định) cho biểu thức không có tiền tố CRON_TZ/TZ
+func (c *Cron) parseSchedule(expr string, loc *time.Location) (cron.Schedule, error) {
+	sched, err := cronParser.Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+	if loc == nil {
+		loc = c.location
+	}
+	if spec, ok := sched.(*cron.SpecSchedule); ok && !strings.HasPrefix(expr, "CRON_TZ=") && !strings.HasPrefix(expr, "TZ=") {
+		spec.Location = loc
 	}
+	return sched, nil
+}
+
+// AddJobs đăng ký các Job. Job lỗi (biểu thức sai, trùng tên...) bị bỏ qua, các job còn lại vẫn được đăng ký
+// và lỗi của mọi job lỗi được gộp lại bằng errors.Join. Dùng Register để nhận JobHandle
+func (c *Cron) AddJobs(jobs ...Job) error {
+	var errs []error
 	for _, job := range jobs {
-		jobName := reflect.TypeOf(job).String()
-		expr, err := resolveCronExpr(job.CronExpr())
-		if err != nil {
-			log.Printf("invalid cronx expr for job %s: %v", jobName, err)
-			continue
+		if _, err := c.Register(job); err != nil {
+			errs = append(errs, err)
 		}
-		if err := c.AddJob(expr, job.Run); err != nil {
-			log.Printf("failed to add cronx job %s: %v", jobName, err)
-			continue
+	}
+	return errors.Join(errs...)
+}
+
+// AddContextJobs giống AddJobs cho ContextJob. Dùng RegisterContext để nhận JobHandle
+func (c *Cron) AddContextJobs(jobs ...ContextJob) error {
+	var errs []error
+	for _, job := range jobs {
+		if _, err := c.RegisterContext(job); err != nil {
+			errs = append(errs, err)
 		}
-		log.Printf("registered cronx job %s với biểu thức [%s]", jobName, expr)
 	}
+	return errors.Join(errs...)
+}
+
+// Register đăng ký Job và trả về JobHandle để điều khiển job
+func (c *Cron) Register(job Job) (*JobHandle, error) {
+	return c.register(job, job.CronExpr(), func(context.Context) error {
+		job.Run()
+		return nil
+	})
+}
+
+// RegisterContext đăng ký ContextJob, cấu hình lấy từ Named, JobTimeout, JobOverlap, JobLocation, JobJitter,
+// JobEnabledKey nếu job implement
+func (c *Cron) RegisterContext(job ContextJob) (*JobHandle, error) {
+	return c.register(job, job.CronExpr(), job.Run)
+}
+
+func (c *Cron) register(job any, cronExpr string, run func(ctx context.Context) error) (*JobHandle, error) {
+	e := c.newEntry(jobName(job), job, run)
+	if err := c.addEntry(cronExpr, e); err != nil {
+		return nil, fmt.Errorf("cronx job %s: %w", e.name, err)
+	}
+	if !e.enabled {
+		log.Printf("registered cronx job %s (disabled by %s)", e.name, e.enabledKey)
+	} else {
+		log.Printf("registered cronx job %s với biểu thức [%s]", e.name, e.spec)
+	}
+	return &JobHandle{cron: c, entry: e}, nil
+}
+
+// jobName lấy từ Named, nếu không có thì dùng tên kiểu của job
+func jobName(job any) string {
+	if n, ok := job.(Named); ok && n.Name() != "" {
+		return n.Name()
+	}
+	return reflect.TypeOf(job).String()
 }
 
 func isValidCronExpr(expr string) bool {
@@ -0,0 +1,41 @@
+package cronx
+
+import (
+	"context"
+	"testing"
+
+	ymlx "github.io/xhkzeroone/goframex/pkg/config"
+)
+
+type lookupJob struct{ expr string }
+
+func (j lookupJob) CronExpr() string { return j.expr }
+func (j lookupJob) Run()             {}
+func (j lookupJob) Name() string     { return "lookup-" + j.expr }
+
+func TestLookupFromConfig(t *testing.T) {
+	cfg, err := ymlx.NewFromMap(map[string]any{
+		"jobs": map[string]any{"cleanup": "0 0 3 * * *", "broken": "not a cron"},
+	})
+	if err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	c := New(WithLookup(cfg))
+	defer c.Shutdown(context.Background())
+
+	h, err := c.Register(lookupJob{expr: "jobs.cleanup"})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if got := h.Info().Schedule; got != "0 0 3 * * *" {
+		t.Errorf("schedule = %q, want resolved expression", got)
+	}
+	if _, err := c.Register(lookupJob{expr: "@every 1m"}); err != nil {
+		t.Errorf("register literal expression: %v", err)
+	}
+	for _, key := range []string{"jobs.broken", "jobs.missing"} {
+		if _, err := c.Register(lookupJob{expr: key}); err == nil {
+			t.Errorf("register %s succeeded", key)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package cronx
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Nguồn kích hoạt một lần chạy
+const (
+	TriggerSchedule = "schedule"
+	TriggerManual   = "manual"
+)
+
+// Execution là kết quả của một lần chạy job
+type Execution struct {
+	ID         string    `json:"id"`
+	Job        string    `json:"job"`
+	Trigger    string    `json:"trigger"`
+	Instance   string    `json:"instance"`
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+	DurationMs int64     `json:"durationMs"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// HistoryStore lưu lịch sử chạy job, xem NewMemoryHistory, NewRedisHistory và NewGormHistory
+type HistoryStore interface {
+	Record(ctx context.Context, exec Execution) error
+	// List trả về tối đa limit lần chạy mới nhất của job, job rỗng là mọi job
+	List(ctx context.Context, job string, limit int) ([]Execution, error)
+}
+
+// WithHistory ghi lại mọi lần chạy job vào store
+func WithHistory(store HistoryStore) Option {
+	return func(c *Cron) {
+		c.history = store
+	}
+}
+
+func newExecution(job, trigger, instance string, start time.Time, err error) Execution {
+	end := time.Now()
+	exec := Execution{
+		ID:         uuid.NewString(),
+		Job:        job,
+		Trigger:    trigger,
+		Instance:   instance,
+		StartedAt:  start,
+		FinishedAt: end,
+		DurationMs: end.Sub(start).Milliseconds(),
+	}
+	if err != nil {
+		exec.Error = err.Error()
+	}
+	return exec
+}
+
+// record lưu exec vào HistoryStore. Context của Cron có thể đã bị hủy khi Shutdown nên dùng context riêng
+func (c *Cron) record(exec Execution) {
+	if c.history == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := c.history.Record(ctx, exec); err != nil {
+		logEntry(ctx).WithField("job", exec.Job).Errorf("cronx record history: %v", err)
+	}
+}
+
+// History trả về lịch sử chạy của job (rỗng là mọi job) từ HistoryStore
+func (c *Cron) History(ctx context.Context, job string, limit int) ([]Execution, error) {
+	if c.history == nil {
+		return nil, nil
+	}
+	return c.history.List(ctx, job, limit)
+}
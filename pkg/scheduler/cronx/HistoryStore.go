@@ -0,0 +1,198 @@
+package cronx
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.io/xhkzeroone/goframex/pkg/cache/redisx"
+	"github.io/xhkzeroone/goframex/pkg/database/gormx"
+	"gorm.io/gorm"
+)
+
+// memoryHistory là ring buffer giữ size lần chạy gần nhất của mọi job
+type memoryHistory struct {
+	mu    sync.Mutex
+	items []Execution
+	next  int
+	full  bool
+}
+
+// NewMemoryHistory lưu tối đa size lần chạy (mặc định 1000) trong bộ nhớ, mất khi khởi động lại
+func NewMemoryHistory(size int) HistoryStore {
+	if size <= 0 {
+		size = 1000
+	}
+	return &memoryHistory{items: make([]Execution, size)}
+}
+
+func (h *memoryHistory) Record(_ context.Context, exec Execution) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.items[h.next] = exec
+	h.next = (h.next + 1) % len(h.items)
+	h.full = h.full || h.next == 0
+	return nil
+}
+
+func (h *memoryHistory) List(_ context.Context, job string, limit int) ([]Execution, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	n := h.next
+	if h.full {
+		n = len(h.items)
+	}
+	var out []Execution
+	// duyệt từ mới nhất về cũ nhất
+	for i := 1; i <= n && (limit <= 0 || len(out) < limit); i++ {
+		exec := h.items[(h.next-i+len(h.items))%len(h.items)]
+		if job == "" || exec.Job == job {
+			out = append(out, exec)
+		}
+	}
+	return out, nil
+}
+
+type redisHistory struct {
+	client *redisx.Redis
+	prefix string
+	size   int64
+}
+
+// NewRedisHistory lưu lịch sử vào các list Redis <prefix>job:<tên job> và <prefix>all,
+// mỗi list giữ tối đa size phần tử (mặc định 1000). prefix rỗng dùng "cronx:history:"
+func NewRedisHistory(client *redisx.Redis, prefix string, size int) HistoryStore {
+	if prefix == "" {
+		prefix = "cronx:history:"
+	}
+	if size <= 0 {
+		size = 1000
+	}
+	return &redisHistory{client: client, prefix: prefix, size: int64(size)}
+}
+
+func (h *redisHistory) Record(ctx context.Context, exec Execution) error {
+	data, err := json.Marshal(exec)
+	if err != nil {
+		return err
+	}
+	pipe := h.client.TxPipeline()
+	for _, key := range []string{h.key(exec.Job), h.key("")} {
+		pipe.LPush(ctx, key, data)
+		pipe.LTrim(ctx, key, 0, h.size-1)
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (h *redisHistory) List(ctx context.Context, job string, limit int) ([]Execution, error) {
+	stop := int64(limit) - 1
+	if limit <= 0 {
+		stop = -1
+	}
+	items, err := h.client.LRange(ctx, h.key(job), 0, stop).Result()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Execution, 0, len(items))
+	for _, item := range items {
+		var exec Execution
+		if err := json.Unmarshal([]byte(item), &exec); err != nil {
+			return nil, err
+		}
+		out = append(out, exec)
+	}
+	return out, nil
+}
+
+func (h *redisHistory) key(job string) string {
+	if job == "" {
+		return h.prefix + "all"
+	}
+	return h.prefix + "job:" + job
+}
+
+// DefaultHistoryTable là bảng mặc định của GormHistory
+const DefaultHistoryTable = "cronx_executions"
+
+type executionRecord struct {
+	ID         string    `gorm:"column:id;primaryKey;size:36"`
+	Job        string    `gorm:"column:job;size:255;not null;index:idx_cronx_executions_job_started,priority:1"`
+	Trigger    string    `gorm:"column:trigger_kind;size:16;not null"`
+	Instance   string    `gorm:"column:instance;size:255"`
+	StartedAt  time.Time `gorm:"column:started_at;not null;index:idx_cronx_executions_job_started,priority:2;index"`
+	FinishedAt time.Time `gorm:"column:finished_at;not null"`
+	DurationMs int64     `gorm:"column:duration_ms;not null"`
+	Error      string    `gorm:"column:error;type:text"`
+}
+
+// GormHistory lưu lịch sử vào bảng qua gormx, dùng Cleanup để xóa bản ghi cũ
+type GormHistory struct {
+	ds    *gormx.DataSource
+	table string
+}
+
+// NewGormHistory với table rỗng dùng DefaultHistoryTable, gọi AutoMigrate hoặc tạo bảng bằng migration
+func NewGormHistory(ds *gormx.DataSource, table string) *GormHistory {
+	if table == "" {
+		table = DefaultHistoryTable
+	}
+	return &GormHistory{ds: ds, table: table}
+}
+
+func (h *GormHistory) AutoMigrate(ctx context.Context) error {
+	return h.db(ctx).AutoMigrate(&executionRecord{})
+}
+
+func (h *GormHistory) Record(ctx context.Context, exec Execution) error {
+	return h.db(ctx).Create(&executionRecord{
+		ID:         exec.ID,
+		Job:        exec.Job,
+		Trigger:    exec.Trigger,
+		Instance:   exec.Instance,
+		StartedAt:  exec.StartedAt,
+		FinishedAt: exec.FinishedAt,
+		DurationMs: exec.DurationMs,
+		Error:      exec.Error,
+	}).Error
+}
+
+func (h *GormHistory) List(ctx context.Context, job string, limit int) ([]Execution, error) {
+	db := h.db(ctx).Order("started_at DESC")
+	if job != "" {
+		db = db.Where("job = ?", job)
+	}
+	if limit > 0 {
+		db = db.Limit(limit)
+	}
+	var records []executionRecord
+	if err := db.Find(&records).Error; err != nil {
+		return nil, err
+	}
+	out := make([]Execution, len(records))
+	for i, r := range records {
+		out[i] = Execution{
+			ID:         r.ID,
+			Job:        r.Job,
+			Trigger:    r.Trigger,
+			Instance:   r.Instance,
+			StartedAt:  r.StartedAt,
+			FinishedAt: r.FinishedAt,
+			DurationMs: r.DurationMs,
+			Error:      r.Error,
+		}
+	}
+	return out, nil
+}
+
+// Cleanup xóa các lần chạy bắt đầu trước before
+func (h *GormHistory) Cleanup(ctx context.Context, before time.Time) (int64, error) {
+	res := h.db(ctx).Where("started_at < ?", before).Delete(&executionRecord{})
+	return res.RowsAffected, res.Error
+}
+
+// bảng lịch sử dùng chung cho mọi tenant
+func (h *GormHistory) db(ctx context.Context) *gorm.DB {
+	return h.ds.Writer(gormx.WithoutTenant(ctx)).Table(h.table)
+}
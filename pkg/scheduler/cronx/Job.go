@@ -1,6 +1,73 @@
 package cronx
 
+import (
+	"context"
+	"time"
+)
+
 type Job interface {
 	CronExpr() string
 	Run()
 }
+
+// ContextJob nhận context bị hủy khi hết timeout hoặc khi Cron.Shutdown, lỗi trả về được ghi log
+type ContextJob interface {
+	CronExpr() string
+	Run(ctx context.Context) error
+}
+
+// Named đặt tên cho job, tên dùng làm khóa của Locker, lịch sử và API quản trị nên cần ổn định
+// và duy nhất. Job không implement Named dùng tên kiểu, ví dụ "*jobs.CleanupJob"
+type Named interface {
+	Name() string
+}
+
+// JobLocation cho phép job chạy theo múi giờ riêng, ghi đè WithLocation.
+// Biểu thức có tiền tố CRON_TZ= được ưu tiên hơn
+type JobLocation interface {
+	Location() *time.Location
+}
+
+// JobJitter cho phép job khai báo độ trễ ngẫu nhiên tối đa, ghi đè WithJitter
+type JobJitter interface {
+	Jitter() time.Duration
+}
+
+// JobEnabledKey khai báo key bool trong config (qua WithLookup) để bật/tắt job, key không tồn tại là bật.
+// Job bị tắt vẫn được đăng ký nhưng không chạy, Cron.Reload áp dụng giá trị mới mà không cần khởi động lại
+type JobEnabledKey interface {
+	EnabledKey() string
+}
+
+// JobTimeout cho phép job tự khai báo thời gian chạy tối đa, ghi đè WithTimeout
+type JobTimeout interface {
+	Timeout() time.Duration
+}
+
+// JobOverlap cho phép job tự khai báo OverlapPolicy, ghi đè WithOverlap
+type JobOverlap interface {
+	Overlap() OverlapPolicy
+}
+
+// OverlapPolicy quyết định cách xử lý tick mới khi lần chạy trước của job chưa xong
+type OverlapPolicy int
+
+const (
+	// OverlapAllow chạy song song nhiều lần (mặc định)
+	OverlapAllow OverlapPolicy = iota
+	// OverlapSkip bỏ qua tick mới
+	OverlapSkip
+	// OverlapQueue giữ lại một tick để chạy ngay sau lần hiện tại, các tick sau đó bị bỏ qua
+	OverlapQueue
+)
+
+func (p OverlapPolicy) String() string {
+	switch p {
+	case OverlapSkip:
+		return "skip"
+	case OverlapQueue:
+		return "queue"
+	default:
+		return "allow"
+	}
+}
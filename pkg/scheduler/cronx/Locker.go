@@ -0,0 +1,215 @@
+package cronx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrLockLost khi gia hạn khóa đã hết hạn hoặc bị instance khác lấy mất
+var ErrLockLost = errors.New("cronx: lock lost")
+
+// Locker là khóa phân tán giúp mỗi lần chạy job chỉ có một instance thực thi
+type Locker interface {
+	// TryLock thử lấy khóa key trong ttl, trả về false nếu instance khác đang giữ
+	TryLock(ctx context.Context, key string, ttl time.Duration) (Lock, bool, error)
+}
+
+// Lock là khóa đang giữ
+type Lock interface {
+	// Refresh gia hạn khóa thêm ttl, Cron gọi định kỳ khi job chạy lâu
+	Refresh(ctx context.Context, ttl time.Duration) error
+	Unlock(ctx context.Context) error
+}
+
+type lockOptions struct {
+	ttl     time.Duration
+	prefix  string
+	wait    bool
+	timeout time.Duration
+	retry   time.Duration
+}
+
+type LockOption func(o *lockOptions)
+
+// WithLockTTL thời gian sống của khóa (mặc định 1 phút), được gia hạn khi job chạy lâu hơn.
+// Instance chết khi đang giữ khóa thì job bị chặn tối đa ttl
+func WithLockTTL(ttl time.Duration) LockOption {
+	return func(o *lockOptions) {
+		o.ttl = ttl
+	}
+}
+
+// WithLockPrefix tiền tố của key khóa (mặc định "cronx:"), key đầy đủ là <prefix><tên job>
+func WithLockPrefix(prefix string) LockOption {
+	return func(o *lockOptions) {
+		o.prefix = prefix
+	}
+}
+
+// WithLockWait chờ tối đa timeout (mặc định bằng ttl) để lấy khóa thay vì bỏ qua lần chạy, thử lại mỗi retry.
+// Dùng khi mọi lần chạy đều phải được thực thi tuần tự, không chỉ một lần cho mỗi tick
+func WithLockWait(timeout, retry time.Duration) LockOption {
+	return func(o *lockOptions) {
+		o.wait, o.timeout, o.retry = true, timeout, retry
+	}
+}
+
+// WithLocker chỉ chạy job khi lấy được khóa, các replica dùng chung Locker sẽ không chạy trùng job.
+// Đồng hồ các instance cần được đồng bộ (NTP), instance bị lệch giờ có thể chạy lại tick đã xong
+func WithLocker(locker Locker, opts ...LockOption) Option {
+	return func(c *Cron) {
+		o := &lockOptions{ttl: time.Minute, prefix: "cronx:", retry: time.Second}
+		for _, opt := range opts {
+			opt(o)
+		}
+		if o.ttl <= 0 {
+			o.ttl = time.Minute
+		}
+		if o.retry <= 0 {
+			o.retry = time.Second
+		}
+		if o.wait && o.timeout <= 0 {
+			o.timeout = o.ttl
+		}
+		c.locker, c.lockOpts = locker, o
+	}
+}
+
+// withLock chạy run khi giữ được khóa của job name, không dùng Locker thì chạy luôn
+func (c *Cron) withLock(name string, run func()) {
+	if c.locker == nil {
+		run()
+		return
+	}
+	ctx := c.ctx
+	key := c.lockOpts.prefix + name
+	log := logEntry(ctx).WithField("job", name)
+	lock, err := c.acquire(ctx, key)
+	if err != nil {
+		log.Errorf("cronx acquire lock %s: %v", key, err)
+		return
+	}
+	if lock == nil {
+		log.Infof("cronx lock %s is held by another instance, tick skipped", key)
+		return
+	}
+
+	stop := c.keepAlive(name, lock)
+	defer func() {
+		stop()
+		// context của Cron có thể đã bị hủy khi Shutdown nhưng khóa vẫn cần được nhả
+		if err := lock.Unlock(context.Background()); err != nil {
+			log.Errorf("cronx release lock %s: %v", key, err)
+		}
+	}()
+	run()
+}
+
+// acquire trả về nil khi khóa đang bị giữ và không chờ (hoặc chờ quá timeout)
+func (c *Cron) acquire(ctx context.Context, key string) (Lock, error) {
+	deadline := time.Now().Add(c.lockOpts.timeout)
+	for {
+		lock, ok, err := c.locker.TryLock(ctx, key, c.lockOpts.ttl)
+		if err != nil || ok {
+			return lock, err
+		}
+		if !c.lockOpts.wait || !time.Now().Add(c.lockOpts.retry).Before(deadline) {
+			return nil, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(c.lockOpts.retry):
+		}
+	}
+}
+
+// keepAlive gia hạn khóa mỗi ttl/3 cho tới khi stop được gọi
+func (c *Cron) keepAlive(name string, lock Lock) (stop func()) {
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(c.lockOpts.ttl / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := lock.Refresh(context.Background(), c.lockOpts.ttl); err != nil {
+					logEntry(c.ctx).WithField("job", name).Errorf("cronx refresh lock: %v", err)
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
+// MemoryLocker khóa trong bộ nhớ của một process, dùng cho test hoặc khi chỉ chạy một instance
+type MemoryLocker struct {
+	mu    sync.Mutex
+	locks map[string]memoryLock
+}
+
+type memoryLock struct {
+	token     string
+	expiresAt time.Time
+}
+
+func NewMemoryLocker() *MemoryLocker {
+	return &MemoryLocker{locks: make(map[string]memoryLock)}
+}
+
+func (l *MemoryLocker) TryLock(_ context.Context, key string, ttl time.Duration) (Lock, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if held, ok := l.locks[key]; ok && time.Now().Before(held.expiresAt) {
+		return nil, false, nil
+	}
+	token := newToken()
+	l.locks[key] = memoryLock{token: token, expiresAt: time.Now().Add(ttl)}
+	return &memoryHandle{locker: l, key: key, token: token}, true, nil
+}
+
+type memoryHandle struct {
+	locker *MemoryLocker
+	key    string
+	token  string
+}
+
+func (h *memoryHandle) Refresh(_ context.Context, ttl time.Duration) error {
+	h.locker.mu.Lock()
+	defer h.locker.mu.Unlock()
+	held, ok := h.locker.locks[h.key]
+	if !ok || held.token != h.token || time.Now().After(held.expiresAt) {
+		return ErrLockLost
+	}
+	held.expiresAt = time.Now().Add(ttl)
+	h.locker.locks[h.key] = held
+	return nil
+}
+
+func (h *memoryHandle) Unlock(_ context.Context) error {
+	h.locker.mu.Lock()
+	defer h.locker.mu.Unlock()
+	if held, ok := h.locker.locks[h.key]; ok && held.token == h.token {
+		delete(h.locker.locks, h.key)
+	}
+	return nil
+}
+
+// newToken sinh giá trị ngẫu nhiên để chỉ instance giữ khóa mới gia hạn/mở được khóa
+func newToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,64 @@
+package cronx
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+type countingJob struct {
+	mu   sync.Mutex
+	runs map[int64]int
+}
+
+func (j *countingJob) CronExpr() string { return "* * * * * *" }
+func (j *countingJob) Name() string     { return "counting" }
+func (j *countingJob) Jitter() time.Duration {
+	return 600 * time.Millisecond
+}
+
+func (j *countingJob) Run() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	// Jitter < 1s nên lần chạy luôn nằm trong giây tới lịch
+	j.runs[time.Now().Unix()]++
+}
+
+func TestLockerRunsEachTickOnce(t *testing.T) {
+	if testing.Short() {
+		t.Skip("waits for scheduled ticks")
+	}
+	locker := NewMemoryLocker()
+	job := &countingJob{runs: make(map[int64]int)}
+
+	// Các replica dùng chung Locker, tick nào cũng chỉ được chạy trên một replica dù jitter khác nhau
+	var replicas []*Cron
+	for i := 0; i < 3; i++ {
+		c := New(WithLocker(locker))
+		if _, err := c.Register(job); err != nil {
+			t.Fatalf("register: %v", err)
+		}
+		replicas = append(replicas, c)
+	}
+	for _, c := range replicas {
+		c.Start()
+	}
+	time.Sleep(3500 * time.Millisecond)
+	for _, c := range replicas {
+		if err := c.Shutdown(context.Background()); err != nil {
+			t.Fatalf("shutdown: %v", err)
+		}
+	}
+
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	if len(job.runs) < 2 {
+		t.Fatalf("job ran in %d distinct seconds, want at least 2", len(job.runs))
+	}
+	for sec, n := range job.runs {
+		if n != 1 {
+			t.Errorf("tick at %s ran %d times", time.Unix(sec, 0).Format(time.TimeOnly), n)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package cronx
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+var (
+	ErrJobNotFound = errors.New("cronx: job not found")
+	ErrShutdown    = errors.New("cronx: scheduler is shut down")
+	ErrJobDisabled = errors.New("cronx: job is disabled by config")
+)
+
+// JobInfo là trạng thái hiện tại của một job trên instance này
+type JobInfo struct {
+	Name     string `json:"name"`
+	Schedule string `json:"schedule"`
+	Overlap  string `json:"overlap"`
+	Timeout  string `json:"timeout,omitempty"`
+	Location string `json:"location"`
+	Jitter   string `json:"jitter,omitempty"`
+	Enabled  bool   `json:"enabled"`
+	Paused   bool   `json:"paused"`
+	Running  int    `json:"running"`
+	// Next là lần chạy theo lịch kế tiếp (rỗng khi Pause hoặc bị tắt), Prev là lần tới lịch gần nhất
+	// không bị Pause, kể cả khi bị bỏ qua do OverlapPolicy hoặc Locker (không tính Trigger)
+	Next          time.Time  `json:"next"`
+	Prev          time.Time  `json:"prev"`
+	Runs          int64      `json:"runs"`
+	Failures      int64      `json:"failures"`
+	LastExecution *Execution `json:"lastExecution,omitempty"`
+}
+
+// Jobs trả về thông tin các job theo thứ tự đăng ký
+func (c *Cron) Jobs() []JobInfo {
+	c.mu.Lock()
+	entries := make([]*entry, len(c.names))
+	for i, name := range c.names {
+		entries[i] = c.entries[name]
+	}
+	c.mu.Unlock()
+
+	infos := make([]JobInfo, len(entries))
+	for i, e := range entries {
+		infos[i] = c.info(e)
+	}
+	return infos
+}
+
+// Job trả về thông tin job theo tên
+func (c *Cron) Job(name string) (JobInfo, error) {
+	e, err := c.entry(name)
+	if err != nil {
+		return JobInfo{}, err
+	}
+	return c.info(e), nil
+}
+
+// Trigger chạy job ngay (bất đồng bộ) kể cả khi đang Pause, vẫn áp dụng OverlapPolicy và Locker.
+// Job bị tắt bằng config trả về ErrJobDisabled
+func (c *Cron) Trigger(name string) error {
+	e, err := c.entry(name)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	enabled := e.enabled
+	c.mu.Unlock()
+	if !enabled {
+		return ErrJobDisabled
+	}
+	if c.ctx.Err() != nil {
+		return ErrShutdown
+	}
+	go c.tick(e, TriggerManual)
+	return nil
+}
+
+// Pause ngừng chạy job theo lịch trên instance này (trạng thái chỉ nằm trong bộ nhớ, không chia sẻ qua Locker),
+// lần chạy đang diễn ra không bị hủy. Tắt job trên mọi replica bằng JobEnabledKey
+func (c *Cron) Pause(name string) error {
+	return c.setPaused(name, true)
+}
+
+// Resume chạy lại job theo lịch sau khi Pause
+func (c *Cron) Resume(name string) error {
+	return c.setPaused(name, false)
+}
+
+// Remove hủy đăng ký job, lần chạy đang diễn ra vẫn tiếp tục tới khi xong
+func (c *Cron) Remove(name string) error {
+	return c.remove(name, nil)
+}
+
+// remove chỉ xóa khi entry hiện tại của name là want (nil là bất kỳ)
+func (c *Cron) remove(name string, want *entry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[name]
+	if !ok || (want != nil && e != want) {
+		return ErrJobNotFound
+	}
+	if e.id != 0 {
+		c.Cron.Remove(e.id)
+	}
+	delete(c.entries, name)
+	c.names = slices.DeleteFunc(c.names, func(n string) bool { return n == name })
+	return nil
+}
+
+func (c *Cron) setPaused(name string, paused bool) error {
+	e, err := c.entry(name)
+	if err != nil {
+		return err
+	}
+	e.mu.Lock()
+	e.paused = paused
+	e.mu.Unlock()
+	return nil
+}
+
+func (c *Cron) entry(name string) (*entry, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[name]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	return e, nil
+}
+
+func (c *Cron) info(e *entry) JobInfo {
+	c.mu.Lock()
+	id, spec, enabled := e.id, e.spec, e.enabled
+	c.mu.Unlock()
+	var scheduled cron.Entry
+	if id != 0 {
+		scheduled = c.Entry(id)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	info := JobInfo{
+		Name:          e.name,
+		Schedule:      spec,
+		Enabled:       enabled,
+		Overlap:       e.overlap.String(),
+		Paused:        e.paused,
+		Running:       e.running,
+		Prev:          e.prev,
+		Runs:          e.runs,
+		Failures:      e.failures,
+		LastExecution: e.last,
+	}
+	if !e.paused {
+		info.Next = scheduled.Next
+	}
+	if e.timeout > 0 {
+		info.Timeout = e.timeout.String()
+	}
+	if e.jitter > 0 {
+		info.Jitter = e.jitter.String()
+	}
+	if sched, ok := scheduled.Schedule.(*cron.SpecSchedule); ok {
+		info.Location = sched.Location.String()
+	}
+	return info
+}
+
+// JobHandle điều khiển một job đã đăng ký, trả về bởi Register và RegisterContext
+type JobHandle struct {
+	cron  *Cron
+	entry *entry
+}
+
+func (h *JobHandle) Name() string {
+	return h.entry.name
+}
+
+// Info trả về trạng thái hiện tại của job
+func (h *JobHandle) Info() JobInfo {
+	return h.cron.info(h.entry)
+}
+
+func (h *JobHandle) Trigger() error {
+	if err := h.check(); err != nil {
+		return err
+	}
+	return h.cron.Trigger(h.entry.name)
+}
+
+func (h *JobHandle) Pause() error {
+	if err := h.check(); err != nil {
+		return err
+	}
+	return h.cron.Pause(h.entry.name)
+}
+
+func (h *JobHandle) Resume() error {
+	if err := h.check(); err != nil {
+		return err
+	}
+	return h.cron.Resume(h.entry.name)
+}
+
+// Remove hủy đăng ký job, handle không dùng được nữa
+func (h *JobHandle) Remove() error {
+	return h.cron.remove(h.entry.name, h.entry)
+}
+
+// check trả về ErrJobNotFound khi job đã bị Remove (kể cả khi tên được đăng ký lại cho job khác)
+func (h *JobHandle) check() error {
+	e, err := h.cron.entry(h.entry.name)
+	if err == nil && e != h.entry {
+		err = ErrJobNotFound
+	}
+	return err
+}
@@ -0,0 +1,61 @@
+package cronx
+
+import (
+	"context"
+	"database/sql"
+	"hash/fnv"
+	"time"
+)
+
+// PostgresLocker dùng pg_try_advisory_lock làm khóa phân tán. Khóa gắn với một kết nối riêng
+// được giữ tới khi Unlock, instance chết thì Postgres tự nhả khóa khi kết nối đóng nên ttl không được dùng
+type PostgresLocker struct {
+	db *sql.DB
+}
+
+// NewPostgresLocker với db là kết nối Postgres, ví dụ lấy từ gormx.DataSource.DB.DB()
+func NewPostgresLocker(db *sql.DB) *PostgresLocker {
+	return &PostgresLocker{db: db}
+}
+
+func (l *PostgresLocker) TryLock(ctx context.Context, key string, _ time.Duration) (Lock, bool, error) {
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	id := advisoryKey(key)
+	var ok bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", id).Scan(&ok); err != nil || !ok {
+		_ = conn.Close()
+		return nil, false, err
+	}
+	return &postgresLock{conn: conn, id: id}, true, nil
+}
+
+type postgresLock struct {
+	conn *sql.Conn
+	id   int64
+}
+
+// Refresh kiểm tra kết nối giữ khóa còn sống, mất kết nối là mất khóa
+func (l *postgresLock) Refresh(ctx context.Context, _ time.Duration) error {
+	if err := l.conn.PingContext(ctx); err != nil {
+		return ErrLockLost
+	}
+	return nil
+}
+
+func (l *postgresLock) Unlock(ctx context.Context) error {
+	_, err := l.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", l.id)
+	if closeErr := l.conn.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// advisoryKey băm tên khóa thành khóa bigint của advisory lock
+func advisoryKey(key string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return int64(h.Sum64())
+}
@@ -0,0 +1,62 @@
+package cronx
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.io/xhkzeroone/goframex/pkg/cache/redisx"
+)
+
+// chỉ gia hạn/xóa khi key vẫn mang token của instance đang giữ khóa
+var (
+	redisRefreshScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+	redisUnlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+)
+
+// RedisLocker dùng SET NX PX của Redis làm khóa phân tán
+type RedisLocker struct {
+	client *redisx.Redis
+}
+
+func NewRedisLocker(client *redisx.Redis) *RedisLocker {
+	return &RedisLocker{client: client}
+}
+
+func (l *RedisLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (Lock, bool, error) {
+	token := newToken()
+	ok, err := l.client.SetNX(ctx, key, token, ttl).Result()
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	return &redisLock{client: l.client, key: key, token: token}, true, nil
+}
+
+type redisLock struct {
+	client *redisx.Redis
+	key    string
+	token  string
+}
+
+func (l *redisLock) Refresh(ctx context.Context, ttl time.Duration) error {
+	n, err := redisRefreshScript.Run(ctx, l.client, []string{l.key}, l.token, ttl.Milliseconds()).Int()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrLockLost
+	}
+	return nil
+}
+
+func (l *redisLock) Unlock(ctx context.Context) error {
+	return redisUnlockScript.Run(ctx, l.client, []string{l.key}, l.token).Err()
+}
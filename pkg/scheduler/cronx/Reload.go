@@ -0,0 +1,111 @@
+package cronx
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+)
+
+// WithReloadInterval gọi Reload định kỳ sau khi Start, chỉ có tác dụng khi Lookup tự đọc lại nguồn của nó
+// (remote config...) mà không có sự kiện thay đổi. Với ymlx.Config dùng OnChange thay thế:
+//
+//	cfg.OnChange(func() { _ = c.Reload() })
+func WithReloadInterval(interval time.Duration) Option {
+	return func(c *Cron) {
+		c.reloadInterval = interval
+	}
+}
+
+// Start chạy scheduler và vòng Reload định kỳ nếu có WithReloadInterval
+func (c *Cron) Start() {
+	c.Cron.Start()
+	if c.reloadInterval > 0 {
+		c.reloadOnce.Do(func() { go c.reloadLoop() })
+	}
+}
+
+func (c *Cron) reloadLoop() {
+	ticker := time.NewTicker(c.reloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.Reload(); err != nil {
+				log.Printf("cronx reload: %v", err)
+			}
+		}
+	}
+}
+
+// Reload đọc lại biểu thức cron và cờ bật/tắt của các job từ Lookup, job có lịch thay đổi được
+// xếp lịch lại mà không ảnh hưởng lần chạy đang diễn ra. Job có giá trị config mới không hợp lệ
+// giữ lịch cũ và lỗi được gộp lại bằng errors.Join. Thường gọi từ ymlx.Config.OnChange
+func (c *Cron) Reload() error {
+	c.mu.Lock()
+	entries := make([]*entry, len(c.names))
+	for i, name := range c.names {
+		entries[i] = c.entries[name]
+	}
+	c.mu.Unlock()
+
+	var errs []error
+	for _, e := range entries {
+		if err := c.reload(e); err != nil {
+			errs = append(errs, fmt.Errorf("cronx job %s: %w", e.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c *Cron) reload(e *entry) error {
+	expr, err := c.resolveCronExpr(e.source)
+	if err != nil {
+		return err
+	}
+	enabled, err := c.resolveEnabled(e.enabledKey)
+	if err != nil {
+		return err
+	}
+	sched, err := c.parseSchedule(expr, e.location)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// job có thể đã bị Remove trong lúc đọc config
+	if c.entries[e.name] != e || (expr == e.spec && enabled == e.enabled) {
+		return nil
+	}
+	oldSpec, wasEnabled := e.spec, e.enabled
+	c.schedule(e, sched, expr, enabled)
+	switch {
+	case !enabled:
+		log.Printf("cronx job %s disabled by %s", e.name, e.enabledKey)
+	case !wasEnabled:
+		log.Printf("cronx job %s enabled với biểu thức [%s]", e.name, expr)
+	default:
+		log.Printf("cronx job %s rescheduled [%s] -> [%s]", e.name, oldSpec, expr)
+	}
+	return nil
+}
+
+// resolveEnabled đọc cờ bật/tắt, không có key hoặc không có Lookup là bật
+func (c *Cron) resolveEnabled(key string) (bool, error) {
+	if key == "" || c.lookup == nil {
+		return true, nil
+	}
+	raw, ok := c.lookup.Lookup(key)
+	if !ok || raw == "" {
+		return true, nil
+	}
+	enabled, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("invalid cronx enabled flag %s=%q: %w", key, raw, err)
+	}
+	return enabled, nil
+}
@@ -0,0 +1,45 @@
+package cronx
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	ymlx "github.io/xhkzeroone/goframex/pkg/config"
+)
+
+func TestReloadOnConfigChange(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yml")
+	if err := os.WriteFile(path, []byte("jobs:\n  cleanup: \"0 0 3 * * *\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := ymlx.New(ymlx.WithDir(dir), ymlx.WithEnv(""))
+	if err != nil {
+		t.Fatalf("config: %v", err)
+	}
+	c := New(WithLookup(cfg))
+	defer c.Shutdown(context.Background())
+	h, err := c.Register(lookupJob{expr: "jobs.cleanup"})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	cfg.OnChange(func() {
+		if err := c.Reload(); err != nil {
+			t.Errorf("reload: %v", err)
+		}
+	})
+
+	if err := os.WriteFile(path, []byte("jobs:\n  cleanup: \"0 30 4 * * *\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for h.Info().Schedule != "0 30 4 * * *" {
+		if time.Now().After(deadline) {
+			t.Fatalf("schedule = %q after config change", h.Info().Schedule)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
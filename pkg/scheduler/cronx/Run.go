@@ -0,0 +1,211 @@
+package cronx
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+	"github.io/xhkzeroone/goframex/pkg/logger/logrusx"
+)
+
+// entry là một job đã đăng ký cùng trạng thái chạy của nó
+type entry struct {
+	name     string
+	run      func(ctx context.Context) error
+	timeout  time.Duration
+	overlap  OverlapPolicy
+	location *time.Location
+	jitter   time.Duration
+	// source là CronExpr khai báo bởi job (biểu thức hoặc key config)
+	source     string
+	enabledKey string
+
+	// id, spec, enabled được bảo vệ bởi Cron.mu; id bằng 0 khi job bị tắt
+	id      cron.EntryID
+	spec    string
+	enabled bool
+
+	mu       sync.Mutex
+	running  int
+	pending  bool
+	paused   bool
+	prev     time.Time
+	runs     int64
+	failures int64
+	last     *Execution
+}
+
+func (c *Cron) newEntry(name string, job any, run func(ctx context.Context) error) *entry {
+	e := &entry{name: name, run: run, timeout: c.timeout, overlap: c.overlap, jitter: c.jitter}
+	if j, ok := job.(JobTimeout); ok {
+		e.timeout = j.Timeout()
+	}
+	if j, ok := job.(JobOverlap); ok {
+		e.overlap = j.Overlap()
+	}
+	if j, ok := job.(JobLocation); ok {
+		e.location = j.Location()
+	}
+	if j, ok := job.(JobJitter); ok {
+		e.jitter = j.Jitter()
+	}
+	if j, ok := job.(JobEnabledKey); ok {
+		e.enabledKey = j.EnabledKey()
+	}
+	return e
+}
+
+// tick được gọi mỗi lần tới lịch hoặc khi Trigger, áp dụng OverlapPolicy trước khi chạy
+func (c *Cron) tick(e *entry, trigger string) {
+	if !c.begin() {
+		return
+	}
+	defer c.wg.Done()
+
+	e.mu.Lock()
+	if trigger == TriggerSchedule {
+		if e.paused {
+			e.mu.Unlock()
+			return
+		}
+		e.prev = time.Now()
+	}
+	if e.running > 0 {
+		switch e.overlap {
+		case OverlapSkip:
+			e.mu.Unlock()
+			logEntry(c.ctx).WithField("job", e.name).Warn("cronx job is still running, tick skipped")
+			return
+		case OverlapQueue:
+			e.pending = true
+			e.mu.Unlock()
+			return
+		}
+	}
+	e.running++
+	e.mu.Unlock()
+
+	// Jitter chỉ áp dụng cho lần chạy đầu của tick theo lịch, lần chạy từ OverlapQueue chạy ngay
+	jitter := trigger == TriggerSchedule && e.jitter > 0
+	for {
+		c.withLock(e.name, func() {
+			// Trễ jitter sau khi đã giữ khóa: replica khác tới lịch cùng lúc thấy khóa đang bị giữ và bỏ qua,
+			// không thể chạy lại tick khi replica này còn đang chờ jitter
+			if jitter && !c.sleepJitter(e) {
+				return
+			}
+			c.execute(e, trigger)
+		})
+		jitter = false
+
+		e.mu.Lock()
+		if e.pending && c.ctx.Err() == nil {
+			e.pending = false
+			e.mu.Unlock()
+			continue
+		}
+		e.pending = false
+		e.running--
+		e.mu.Unlock()
+		return
+	}
+}
+
+// sleepJitter chờ ngẫu nhiên trong [0, e.jitter), trả về false khi Cron Shutdown hoặc job bị Pause trong lúc chờ
+func (c *Cron) sleepJitter(e *entry) bool {
+	select {
+	case <-c.ctx.Done():
+		return false
+	case <-time.After(rand.N(e.jitter)):
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return !e.paused
+}
+
+// begin đăng ký một lần chạy, trả về false khi Cron đã Shutdown
+func (c *Cron) begin() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return false
+	}
+	c.wg.Add(1)
+	return true
+}
+
+// execute chạy job với timeout, lỗi và panic được ghi log qua logrusx và lưu vào lịch sử
+func (c *Cron) execute(e *entry, trigger string) {
+	ctx := c.ctx
+	if e.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	stack, err := safeRun(ctx, e.run)
+	log := logEntry(ctx).WithFields(logrus.Fields{"job": e.name, "duration": time.Since(start).String()})
+	switch {
+	case stack != nil:
+		log.WithField("stack", string(stack)).Errorf("cronx job panicked: %v", err)
+	case err != nil:
+		log.Errorf("cronx job failed: %v", err)
+	}
+
+	exec := newExecution(e.name, trigger, c.instance, start, err)
+	e.mu.Lock()
+	e.runs++
+	if err != nil {
+		e.failures++
+	}
+	e.last = &exec
+	e.mu.Unlock()
+	c.record(exec)
+}
+
+// safeRun trả về stack khi job panic
+func safeRun(ctx context.Context, run func(ctx context.Context) error) (stack []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			stack, err = debug.Stack(), fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return nil, run(ctx)
+}
+
+// Shutdown dừng scheduler, hủy context của các job đang chạy và chờ chúng kết thúc.
+// Trả về ctx.Err() nếu ctx hết hạn trước khi mọi job dừng
+func (c *Cron) Shutdown(ctx context.Context) error {
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+
+	c.cancel()
+	stopped := c.Cron.Stop()
+	done := make(chan struct{})
+	go func() {
+		<-stopped.Done()
+		c.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func logEntry(ctx context.Context) *logrus.Entry {
+	if logrusx.Log == nil {
+		return logrus.NewEntry(logrus.StandardLogger())
+	}
+	return logrusx.WithContext(ctx)
+}